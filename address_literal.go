@@ -0,0 +1,132 @@
+package emailaddressnormalize
+
+import (
+	"net"
+	"strings"
+)
+
+// AddressLiteralTagIPv6 is the Standardized-tag of IPv6 address literal.
+const AddressLiteralTagIPv6 = "IPv6"
+
+// BareIPDomainPolicy select how IP address given as domain part without
+// brackets is handled.
+type BareIPDomainPolicy int
+
+// Policies of IP address without brackets.
+const (
+	// BareIPDomainBracket treat the address as address literal and add brackets.
+	BareIPDomainBracket BareIPDomainPolicy = iota
+
+	// BareIPDomainReject reject the address with ErrBareIPDomain.
+	BareIPDomainReject
+
+	// BareIPDomainAsDNSName check the address as a DNS name.
+	BareIPDomainAsDNSName
+)
+
+// isLdhString check if given text `s` is a RFC 5321 Ldh-str.
+func isLdhString(s string) bool {
+	if (len(s) == 0) || (s[len(s)-1] == '-') {
+		return false
+	}
+	for idx, ch := range s {
+		switch {
+		case (ch >= 'a') && (ch <= 'z'):
+		case (ch >= 'A') && (ch <= 'Z'):
+		case (ch >= '0') && (ch <= '9'):
+		case (ch == '-') && (idx > 0):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isDcontent check if given character `ch` is a RFC 5321 dcontent.
+func isDcontent(ch rune) bool {
+	return ((ch >= 33) && (ch <= 90)) || ((ch >= 94) && (ch <= 126))
+}
+
+// parseIPv6AddressLiteral parse given IPv6 address `addr` and return it in
+// canonical (compressed, lower-case) form. IPv4-mapped address is rejected
+// unless `allowIPv4Mapped` is set.
+func parseIPv6AddressLiteral(addr string, allowIPv4Mapped bool) (canonicalAddr string, ip net.IP, err error) {
+	if ip = net.ParseIP(addr); (ip == nil) || (strings.IndexByte(addr, ':') < 0) {
+		return "", nil, ErrInvalidAddressLiteral
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		if !allowIPv4Mapped {
+			return "", nil, ErrInvalidAddressLiteral
+		}
+		return "::ffff:" + ip4.String(), ip, nil
+	}
+	return ip.String(), ip, nil
+}
+
+// parseAddressLiteral parse content of address literal (without brackets)
+// with RFC 5321 rules and return it in canonical form. IPv6 address literal
+// without tag is accepted and the `IPv6:` tag is added. The returned tag is empty for
+// IPv4 address literal, and the returned IP is nil for General-address-literal.
+func parseAddressLiteral(literal string, allowIPv4Mapped bool) (canonicalLiteral, tag string, ip net.IP, err error) {
+	idx := strings.IndexByte(literal, ':')
+	if idx < 0 {
+		// IPv4-address-literal = Snum 3("."  Snum)
+		if ip = net.ParseIP(literal); (ip == nil) || (ip.To4() == nil) {
+			return "", "", nil, ErrInvalidAddressLiteral
+		}
+		return ip.To4().String(), "", ip, nil
+	}
+	// IPv6-address-literal = "IPv6:" IPv6-addr
+	if strings.EqualFold(literal[:idx], AddressLiteralTagIPv6) {
+		if canonicalLiteral, ip, err = parseIPv6AddressLiteral(literal[idx+1:], allowIPv4Mapped); nil != err {
+			return
+		}
+		return AddressLiteralTagIPv6 + ":" + canonicalLiteral, AddressLiteralTagIPv6, ip, nil
+	}
+	if canonicalLiteral, ip, err = parseIPv6AddressLiteral(literal, allowIPv4Mapped); nil == err {
+		return AddressLiteralTagIPv6 + ":" + canonicalLiteral, AddressLiteralTagIPv6, ip, nil
+	}
+	// General-address-literal = Standardized-tag ":" 1*dcontent
+	tag, content := literal[:idx], literal[idx+1:]
+	if (!isLdhString(tag)) || (len(content) == 0) {
+		return "", "", nil, ErrInvalidAddressLiteral
+	}
+	for _, ch := range content {
+		if !isDcontent(ch) {
+			return "", "", nil, ErrInvalidAddressLiteral
+		}
+	}
+	return literal, tag, nil, nil
+}
+
+// checkAddressLiteral parse collected address literal and replace domain
+// part with its canonical form.
+func (n *normalizeInstance) checkAddressLiteral(opt *NormalizeOption) (err error) {
+	if n.addressLiteralMalformed || (!n.addressLiteralClosed) || (len(n.domainPart) > 0) {
+		return ErrInvalidAddressLiteral
+	}
+	canonicalLiteral, tag, ip, err := parseAddressLiteral(string(n.addressLiteral), opt.AllowIPv4MappedAddressLiteral)
+	if nil != err {
+		return
+	}
+	n.domainPart = ([]rune)(canonicalLiteral)
+	n.addressLiteralTag = tag
+	n.addressLiteralIP = ip
+	return nil
+}
+
+// checkIPAddressClass classify IP address of address literal and reject it
+// if its class is in RejectIPAddressClasses option.
+func (n *normalizeInstance) checkIPAddressClass(opt *NormalizeOption) (err error) {
+	if n.addressLiteralIP == nil {
+		return nil
+	}
+	n.ipAddressClass = classifyIPAddress(n.addressLiteralIP)
+	if rejected := n.ipAddressClass & opt.RejectIPAddressClasses; rejected != 0 {
+		return &ErrRejectedIPAddressClass{
+			Class:   rejected,
+			Address: n.resultDomainPart(),
+		}
+	}
+	return nil
+}
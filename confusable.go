@@ -0,0 +1,161 @@
+package emailaddressnormalize
+
+//go:generate go run make_confusables_table.go
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MixedScriptPolicy select how addresses with mixed-script local part or
+// domain labels are handled.
+type MixedScriptPolicy int
+
+// Policies of mixed-script check.
+const (
+	// MixedScriptIgnore skip mixed-script check.
+	MixedScriptIgnore MixedScriptPolicy = iota
+
+	// MixedScriptFlag report mixed-script parts with flags in ParsedAddress.
+	MixedScriptFlag
+
+	// MixedScriptReject reject address with mixed-script parts.
+	MixedScriptReject
+)
+
+// Scripts commonly used together in a single label (UTS #39 highly restrictive level).
+var allowedScriptCombinations = []map[string]bool{
+	{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true},
+	{"Latin": true, "Han": true, "Bopomofo": true},
+	{"Latin": true, "Han": true, "Hangul": true},
+}
+
+// scriptTable is a script candidate of runeScript.
+type scriptTable struct {
+	name  string
+	table *unicode.RangeTable
+}
+
+// scriptTables are the scripts looked up by runeScript in order. Commonly
+// used scripts come first, followed by other scripts in order of name.
+var scriptTables = makeScriptTables("Latin", "Han", "Hiragana", "Katakana", "Hangul", "Bopomofo",
+	"Cyrillic", "Greek", "Arabic", "Hebrew", "Devanagari", "Thai", "Armenian", "Georgian")
+
+func makeScriptTables(commonScripts ...string) (result []scriptTable) {
+	added := map[string]bool{
+		"Common":    true,
+		"Inherited": true,
+	}
+	for _, name := range commonScripts {
+		result = append(result, scriptTable{name, unicode.Scripts[name]})
+		added[name] = true
+	}
+	otherScripts := make([]string, 0, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		if !added[name] {
+			otherScripts = append(otherScripts, name)
+		}
+	}
+	sort.Strings(otherScripts)
+	for _, name := range otherScripts {
+		result = append(result, scriptTable{name, unicode.Scripts[name]})
+	}
+	return
+}
+
+// runeScript return name of the script of given character.
+// Empty string is returned for Common and Inherited characters.
+func runeScript(ch rune) string {
+	if ch <= unicode.MaxASCII {
+		if unicode.IsLetter(ch) {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.Is(unicode.Common, ch) || unicode.Is(unicode.Inherited, ch) {
+		return ""
+	}
+	for _, script := range scriptTables {
+		if unicode.Is(script.table, ch) {
+			return script.name
+		}
+	}
+	return ""
+}
+
+// isMixedScript check if given text contain characters of scripts which are
+// not commonly used together.
+func isMixedScript(text string) bool {
+	scripts := make(map[string]bool)
+	for _, ch := range text {
+		if name := runeScript(ch); name != "" {
+			scripts[name] = true
+		}
+	}
+	if len(scripts) <= 1 {
+		return false
+	}
+	for _, allowedScripts := range allowedScriptCombinations {
+		allowed := true
+		for name := range scripts {
+			if !allowedScripts[name] {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// Skeleton return the UTS #39 skeleton of given (normalized) email address.
+// Addresses which are visually confusable have the same skeleton.
+func Skeleton(emailAddress string) string {
+	var b strings.Builder
+	for _, ch := range norm.NFD.String(emailAddress) {
+		if prototype, ok := confusablesTable[ch]; ok {
+			b.WriteString(prototype)
+		} else {
+			b.WriteRune(ch)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// checkMixedScript check local part and each label of domain part for mixing scripts.
+func (n *normalizeInstance) checkMixedScript(opt *NormalizeOption, c *violationCollector) (shouldStop bool) {
+	if n.localPartNormalizer.hasNonASCIICharacter && isMixedScript(string(n.localPartNormalizer.localPart)) {
+		n.localPartMixedScript = true
+		if opt.MixedScriptPolicy == MixedScriptReject {
+			if c.report(ErrMixedScriptLocalPart) {
+				return true
+			}
+		}
+	}
+	if !n.dnClass.IsIDNA() && !strings.Contains(string(n.domainPart), "xn--") {
+		return false
+	}
+	domainPart := n.domainULabel
+	if domainPart == "" {
+		var err error
+		if domainPart, err = idna.Punycode.ToUnicode(string(n.domainPart)); nil != err {
+			domainPart = string(n.domainPart)
+		}
+	}
+	for _, label := range strings.Split(domainPart, ".") {
+		if isMixedScript(label) {
+			n.domainMixedScript = true
+			if opt.MixedScriptPolicy == MixedScriptReject {
+				return c.report(ErrMixedScriptDomainLabel)
+			}
+			break
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package emailaddressnormalize_test
+
+import (
+	"testing"
+
+	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
+)
+
+func TestSkeleton(t *testing.T) {
+	if emailaddressnormalize.Skeleton("user@pаypal.com") != emailaddressnormalize.Skeleton("user@paypal.com") {
+		t.Errorf("expecting same skeleton for Cyrillic a and Latin a")
+	}
+	if emailaddressnormalize.Skeleton("user@paypal.com") == emailaddressnormalize.Skeleton("user@paypai.com") {
+		t.Errorf("expecting different skeleton")
+	}
+}
+
+func TestNormalizeEmailAddress_MixedScript(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowLocalPartInternationalChars: true,
+		MixedScriptPolicy:                emailaddressnormalize.MixedScriptReject,
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@pаypal.com", "", "", true); err != emailaddressnormalize.ErrMixedScriptDomainLabel {
+		t.Errorf("unexpect error content for mixed-script domain: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@xn--pypal-4ve.com", "", "", true); err != emailaddressnormalize.ErrMixedScriptDomainLabel {
+		t.Errorf("unexpect error content for mixed-script domain in A-label form: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "pаypal@example.com", "", "", true); err != emailaddressnormalize.ErrMixedScriptLocalPart {
+		t.Errorf("unexpect error content for mixed-script local part: %v", err)
+	}
+	doNormalizeEmailAddressTest(t, opt, "ユーザー名@例え.jp", "ユーザー名@例え.jp", "ユーザー名@例え.jp", false)
+	doNormalizeEmailAddressTest(t, opt, "почта@пример.рф", "почта@пример.рф", "почта@пример.рф", false)
+	opt.MixedScriptPolicy = emailaddressnormalize.MixedScriptFlag
+	parsedAddress, err := emailaddressnormalize.ParseEmailAddress("pаypal@pаypal.com", opt)
+	if nil != err {
+		t.Fatalf("unexpect error: %v", err)
+	}
+	if (!parsedAddress.LocalPartIsMixedScript) || (!parsedAddress.DomainIsMixedScript) {
+		t.Errorf("expecting mixed-script flags: %#v", parsedAddress)
+	}
+	if parsedAddress.Skeleton() != emailaddressnormalize.Skeleton("paypal@paypal.com") {
+		t.Errorf("unexpect skeleton: %s", parsedAddress.Skeleton())
+	}
+}
@@ -0,0 +1,55 @@
+package emailaddressnormalize
+
+import (
+	"strings"
+)
+
+// DomainAliasMap map alias domains to canonical domains.
+// Key starts with dot (eg: ".example.net") match all sub-domains of the key.
+type DomainAliasMap map[string]string
+
+// Canonicalize return canonical domain of given domain part. Exact match is
+// looked up first and then the longest matching suffix. Given domain part is
+// returned as-is when there is no match.
+// It is a DomainCanonicalizeFunc.
+func (m DomainAliasMap) Canonicalize(domainPart string) (canonicalDomain string) {
+	if canonicalDomain, ok := m[domainPart]; ok {
+		return canonicalDomain
+	}
+	for idx := strings.IndexByte(domainPart, '.'); idx >= 0; {
+		if canonicalDomain, ok := m[domainPart[idx:]]; ok {
+			return canonicalDomain
+		}
+		nextIdx := strings.IndexByte(domainPart[idx+1:], '.')
+		if nextIdx < 0 {
+			break
+		}
+		idx += nextIdx + 1
+	}
+	return domainPart
+}
+
+// canonicalizeDomain compute the domain part for normalized address.
+// Mailbox in domain part is extracted when subdomain addressing applies.
+// CAUTION: **Must** invoke after `check()` method.
+func (n *normalizeInstance) canonicalizeDomain(opt *NormalizeOption) {
+	n.normalizedDomainPart = string(n.domainPart)
+	if n.checkedIsIPLiteralPositive {
+		return
+	}
+	if opt.UnicodeNormalization {
+		n.normalizedDomainPart = foldText(n.normalizedDomainPart)
+	}
+	if opt.SubdomainAddressingWith != nil {
+		domainPart := n.normalizedDomainPart
+		if baseDomain := opt.SubdomainAddressingWith(domainPart); (baseDomain != "") && strings.HasSuffix(domainPart, "."+baseDomain) {
+			if mailbox := domainPart[:len(domainPart)-len(baseDomain)-1]; (mailbox != "") && (strings.IndexByte(mailbox, '.') < 0) {
+				n.subdomainMailbox = mailbox
+				n.normalizedDomainPart = baseDomain
+			}
+		}
+	}
+	if opt.CanonicalizeDomainWith != nil {
+		n.normalizedDomainPart = opt.CanonicalizeDomainWith(n.normalizedDomainPart)
+	}
+}
@@ -0,0 +1,37 @@
+package emailaddressnormalize_test
+
+import (
+	"testing"
+
+	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
+)
+
+func TestDomainAliasMap_Canonicalize(t *testing.T) {
+	aliases := emailaddressnormalize.DomainAliasMap{
+		"googlemail.com":  "gmail.com",
+		".example.net":    "example.com",
+		".a.example.net":  "a.example.com",
+		"b.a.example.net": "b.example.com",
+	}
+	for _, c := range [][2]string{
+		{"googlemail.com", "gmail.com"},
+		{"mail.googlemail.com", "mail.googlemail.com"},
+		{"example.net", "example.net"},
+		{"mail.example.net", "example.com"},
+		{"mail.a.example.net", "a.example.com"},
+		{"b.a.example.net", "b.example.com"},
+		{"example.org", "example.org"},
+	} {
+		if result := aliases.Canonicalize(c[0]); result != c[1] {
+			t.Errorf("unexpect canonical domain of %s: %s, expect %s", c[0], result, c[1])
+		}
+	}
+	opt := &emailaddressnormalize.NormalizeOption{
+		CanonicalizeDomainWith: aliases.Canonicalize,
+		RemoveLocalPartDotsWith: func(domainPart string) (removeDots bool) {
+			return domainPart == "gmail.com"
+		},
+	}
+	doNormalizeEmailAddressTest(t, opt, "First.Last@GoogleMail.com", "first.last@googlemail.com", "firstlast@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last@Mail.Example.net", "first.last@mail.example.net", "first.last@example.com", false)
+}
@@ -0,0 +1,68 @@
+package emailaddressnormalize
+
+// DomainCharacterClass represent the kinds of characters found in domain part.
+type DomainCharacterClass uint8
+
+// Kinds of characters in DomainCharacterClass.
+const (
+	DomainCharacterIDNA DomainCharacterClass = 1 << iota
+	DomainCharacterDecimal
+	DomainCharacterHex
+	DomainCharacterDot
+	DomainCharacterColon
+	DomainCharacterOther
+)
+
+// IsIDNA check if domain part contain international characters.
+func (c DomainCharacterClass) IsIDNA() bool {
+	return (c & DomainCharacterIDNA) != 0
+}
+
+// HasDecimal check if domain part contain decimal digits.
+func (c DomainCharacterClass) HasDecimal() bool {
+	return (c & DomainCharacterDecimal) != 0
+}
+
+// HasHex check if domain part contain hexadecimal letters (a-f).
+func (c DomainCharacterClass) HasHex() bool {
+	return (c & DomainCharacterHex) != 0
+}
+
+// HasDot check if domain part contain dots.
+func (c DomainCharacterClass) HasDot() bool {
+	return (c & DomainCharacterDot) != 0
+}
+
+// HasColon check if domain part contain colons.
+func (c DomainCharacterClass) HasColon() bool {
+	return (c & DomainCharacterColon) != 0
+}
+
+// HasOtherCharacters check if domain part contain characters other than
+// decimal digits, hexadecimal letters, dots and colons.
+func (c DomainCharacterClass) HasOtherCharacters() bool {
+	return (c & DomainCharacterOther) != 0
+}
+
+// String return compact representation of the classification.
+func (c DomainCharacterClass) String() (result string) {
+	if c.IsIDNA() {
+		result += "I"
+	}
+	if c.HasDecimal() {
+		result += "3"
+	}
+	if c.HasHex() {
+		result += "X"
+	}
+	if c.HasDot() {
+		result += "."
+	}
+	if c.HasColon() {
+		result += ":"
+	}
+	if c.HasOtherCharacters() {
+		result += "C"
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package emailaddressnormalize
+
+import (
+	"golang.org/x/net/idna"
+)
+
+// DomainForm select the form of domain part in results.
+type DomainForm int
+
+// Forms of domain part.
+const (
+	// DomainFormAsGiven keep domain part as given (with lower casing and dot fixes).
+	DomainFormAsGiven DomainForm = iota
+
+	// DomainFormALabel convert domain part into A-label (xn--) form.
+	DomainFormALabel
+
+	// DomainFormULabel convert domain part into U-label (Unicode) form.
+	DomainFormULabel
+)
+
+// idnaProfile map and validate domain part for lookup. Hyphen placement is
+// checked by checkDomainSyntax() instead.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.CheckHyphens(false))
+
+// convertDomainForm convert domain part into A-label and U-label forms with
+// UTS #46 mapping and replace domain part with the form selected by `domainForm`.
+func (n *normalizeInstance) convertDomainForm(domainForm DomainForm) (err error) {
+	domainPart := string(n.domainPart)
+	uLabel, err := idnaProfile.ToUnicode(domainPart)
+	if nil != err {
+		return &ErrInvalidIDNADomain{
+			Domain: domainPart,
+			Err:    err,
+		}
+	}
+	aLabel, err := idnaProfile.ToASCII(uLabel)
+	if nil != err {
+		return &ErrInvalidIDNADomain{
+			Domain: domainPart,
+			Err:    err,
+		}
+	}
+	n.domainALabel = aLabel
+	n.domainULabel = uLabel
+	if domainForm == DomainFormALabel {
+		n.domainPart = ([]rune)(aLabel)
+	} else {
+		n.domainPart = ([]rune)(uLabel)
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package emailaddressnormalize
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+const domainLabelLengthLimit = 63
+
+// isAllNumeric check if given label `label` contain only decimal digits.
+func isAllNumeric(label string) bool {
+	for _, ch := range label {
+		if (ch < '0') || (ch > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// domainPartInALabel return domain part in A-label form for syntax check.
+func (n *normalizeInstance) domainPartInALabel() string {
+	if n.domainALabel != "" {
+		return n.domainALabel
+	}
+	domainPart := string(n.domainPart)
+	if !n.dnClass.IsIDNA() {
+		return domainPart
+	}
+	if aLabel, err := idna.Punycode.ToASCII(domainPart); nil == err {
+		return aLabel
+	}
+	return domainPart
+}
+
+// checkDomainSyntax check labels and length of domain part with RFC 1035 and
+// RFC 5321 rules. Each kind of violation is reported at most once.
+func (n *normalizeInstance) checkDomainSyntax(opt *NormalizeOption, c *violationCollector) (shouldStop bool) {
+	if n.hasInvalidDomainCharacter {
+		if c.report(violationAt(opt, ErrDomainInvalidCharacter, n.invalidDomainCharacterAt)) {
+			return true
+		}
+	}
+	hasEmptyLabel := n.hasEmptyDomainLabel
+	if hasEmptyLabel {
+		if c.report(violationAt(opt, ErrDomainLabelEmpty, n.emptyDomainLabelAt)) {
+			return true
+		}
+	}
+	domainPart := n.domainPartInALabel()
+	if len(domainPart) > domainLengthLimit {
+		if c.report(ErrDomainTooLong) {
+			return true
+		}
+	}
+	// RFC 5321 Domain does not have root label, trailing dot makes an empty label.
+	labels := strings.Split(domainPart, ".")
+	var hasTooLongLabel, hasHyphenAtLabelBoundary bool
+	for _, label := range labels {
+		if (len(label) == 0) && !hasEmptyLabel {
+			hasEmptyLabel = true
+			if c.report(ErrDomainLabelEmpty) {
+				return true
+			}
+		} else if (len(label) > domainLabelLengthLimit) && !hasTooLongLabel {
+			hasTooLongLabel = true
+			if c.report(ErrDomainLabelTooLong) {
+				return true
+			}
+		}
+		if (strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-")) && !hasHyphenAtLabelBoundary {
+			hasHyphenAtLabelBoundary = true
+			if c.report(ErrDomainLabelHyphenAtBoundary) {
+				return true
+			}
+		}
+	}
+	if opt.RejectNumericTLD && isAllNumeric(labels[len(labels)-1]) {
+		if c.report(ErrDomainNumericTLD) {
+			return true
+		}
+	}
+	return false
+}
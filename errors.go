@@ -2,62 +2,374 @@ package emailaddressnormalize
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
+// Machine-readable codes of errors. See ErrorCode().
+const (
+	ErrorCodeGivenAddressTooShort                      = "given_address_too_short"
+	ErrorCodeGivenAddressHasIPLiteral                  = "given_address_has_ip_literal"
+	ErrorCodeGivenAddressNeedQuote                     = "given_address_need_quote"
+	ErrorCodeGivenAddressContainSpecialCharacter       = "given_address_contain_special_character"
+	ErrorCodeGivenAddressLocalPartContainI18NCharacter = "given_address_local_part_contain_i18n_character"
+	ErrorCodeEmptyDomainAfterCheck                     = "empty_domain_after_check"
+	ErrorCodeEmptyLocalPartAfterCheck                  = "empty_local_part_after_check"
+	ErrorCodeEmptyLocalPartAfterNormalize              = "empty_local_part_after_normalize"
+	ErrorCodeUnknownDomainCharacterCombination         = "unknown_domain_character_combination"
+	ErrorCodeInvalidIDNADomain                         = "invalid_idna_domain"
+	ErrorCodeDomainTooLong                             = "domain_too_long"
+	ErrorCodeDomainInvalidCharacter                    = "domain_invalid_character"
+	ErrorCodeDomainLabelEmpty                          = "domain_label_empty"
+	ErrorCodeDomainLabelTooLong                        = "domain_label_too_long"
+	ErrorCodeDomainLabelHyphenAtBoundary               = "domain_label_hyphen_at_boundary"
+	ErrorCodeDomainNumericTLD                          = "domain_numeric_tld"
+	ErrorCodeLocalPartTooLong                          = "local_part_too_long"
+	ErrorCodePathTooLong                               = "path_too_long"
+	ErrorCodeNoSubAddressSeparator                     = "no_sub_address_separator"
+	ErrorCodeMixedScriptLocalPart                      = "mixed_script_local_part"
+	ErrorCodeMixedScriptDomainLabel                    = "mixed_script_domain_label"
+	ErrorCodeGivenAddressContainControlCharacter       = "given_address_contain_control_character"
+	ErrorCodeGivenAddressContainFormatCharacter        = "given_address_contain_format_character"
+	ErrorCodeGivenAddressContainBidiControl            = "given_address_contain_bidi_control"
+	ErrorCodeUnbalancedParenthesis                     = "unbalanced_parenthesis"
+	ErrorCodeInvalidAddressLiteral                     = "invalid_address_literal"
+	ErrorCodeRejectedIPAddressClass                    = "rejected_ip_address_class"
+	ErrorCodeBareIPDomain                              = "bare_ip_domain"
+	ErrorCodeStrictRFCViolation                        = "strict_rfc_violation"
+)
+
+// codedError is an error with machine-readable code.
+type codedError struct {
+	code    string
+	message string
+}
+
+func newCodedError(code, message string) error {
+	return &codedError{
+		code:    code,
+		message: message,
+	}
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+func (e *codedError) ErrorCode() string {
+	return e.code
+}
+
 // ErrGivenAddressTooShort indicate given email address is too short.
-var ErrGivenAddressTooShort = errors.New("given email address is too short")
+var ErrGivenAddressTooShort = newCodedError(ErrorCodeGivenAddressTooShort, "given email address is too short")
 
 // ErrGivenAddressHasIPLiteral indicate given email address has IP literal as domain part.
-var ErrGivenAddressHasIPLiteral = errors.New("given email address has IP literal as domain part")
+var ErrGivenAddressHasIPLiteral = newCodedError(ErrorCodeGivenAddressHasIPLiteral, "given email address has IP literal as domain part")
 
 // ErrGivenAddressNeedQuote indicate given email address needs quote.
-var ErrGivenAddressNeedQuote = errors.New("given email address have to be quoted")
+var ErrGivenAddressNeedQuote = newCodedError(ErrorCodeGivenAddressNeedQuote, "given email address have to be quoted")
 
 // ErrGivenAddressContainSpecialCharacter indicate given email address contain special characters may harmful to MTA.
-var ErrGivenAddressContainSpecialCharacter = errors.New("given email address have special character")
+var ErrGivenAddressContainSpecialCharacter = newCodedError(ErrorCodeGivenAddressContainSpecialCharacter, "given email address have special character")
 
 // ErrGivenAddressLocalPartContainI18NCharacter indicate local part of given email address contain international character.
-var ErrGivenAddressLocalPartContainI18NCharacter = errors.New("local part of given email address have i18n character")
+var ErrGivenAddressLocalPartContainI18NCharacter = newCodedError(ErrorCodeGivenAddressLocalPartContainI18NCharacter, "local part of given email address have i18n character")
+
+// ErrGivenAddressContainControlCharacter indicate given email address contain control characters (eg: CR, LF).
+var ErrGivenAddressContainControlCharacter = newCodedError(ErrorCodeGivenAddressContainControlCharacter, "given email address have control character")
+
+// ErrGivenAddressContainFormatCharacter indicate given email address contain format characters (eg: zero-width joiner).
+var ErrGivenAddressContainFormatCharacter = newCodedError(ErrorCodeGivenAddressContainFormatCharacter, "given email address have format character")
+
+// ErrGivenAddressContainBidiControl indicate given email address contain bidirectional control characters.
+var ErrGivenAddressContainBidiControl = newCodedError(ErrorCodeGivenAddressContainBidiControl, "given email address have bidi control character")
+
+// ErrUnbalancedParenthesis indicate given email address have unclosed comment or stray close parenthesis.
+var ErrUnbalancedParenthesis = newCodedError(ErrorCodeUnbalancedParenthesis, "given email address have unbalanced parenthesis")
+
+// ErrInvalidAddressLiteral indicate address literal in domain part does not follow RFC 5321 syntax.
+var ErrInvalidAddressLiteral = newCodedError(ErrorCodeInvalidAddressLiteral, "given email address have invalid address literal")
+
+// ErrBareIPDomain indicate domain part is an IP address without brackets.
+var ErrBareIPDomain = newCodedError(ErrorCodeBareIPDomain, "given email address have IP address without brackets as domain")
+
+// ErrStrictRFCViolation indicate given email address does not match RFC 5321 Mailbox grammar.
+var ErrStrictRFCViolation = newCodedError(ErrorCodeStrictRFCViolation, "given email address does not match RFC 5321 grammar")
 
 // ErrEmptyDomainAfterCheck indicate domain part of given address become empty after check process.
-var ErrEmptyDomainAfterCheck = errors.New("domain part become empty")
+var ErrEmptyDomainAfterCheck = newCodedError(ErrorCodeEmptyDomainAfterCheck, "domain part become empty")
 
 // ErrEmptyLocalPartAfterCheck indicate local part of given address become empty after check process.
-var ErrEmptyLocalPartAfterCheck = errors.New("local part become empty after check")
+var ErrEmptyLocalPartAfterCheck = newCodedError(ErrorCodeEmptyLocalPartAfterCheck, "local part become empty after check")
 
 // ErrEmptyLocalPartAfterNormalize indicate local part of given address become empty after normalize process.
-var ErrEmptyLocalPartAfterNormalize = errors.New("domain part become empty after normalize")
+var ErrEmptyLocalPartAfterNormalize = newCodedError(ErrorCodeEmptyLocalPartAfterNormalize, "domain part become empty after normalize")
+
+// ErrDomainTooLong indicate domain part is longer than 255 octets in A-label form.
+var ErrDomainTooLong = newCodedError(ErrorCodeDomainTooLong, "domain part is too long")
+
+// ErrDomainInvalidCharacter indicate domain part contain character which is not letter, digit, hyphen or dot.
+var ErrDomainInvalidCharacter = newCodedError(ErrorCodeDomainInvalidCharacter, "domain part have invalid character")
+
+// ErrDomainLabelEmpty indicate domain part contain empty label.
+var ErrDomainLabelEmpty = newCodedError(ErrorCodeDomainLabelEmpty, "domain part have empty label")
+
+// ErrDomainLabelTooLong indicate domain part contain label longer than 63 octets in A-label form.
+var ErrDomainLabelTooLong = newCodedError(ErrorCodeDomainLabelTooLong, "domain part have too long label")
+
+// ErrDomainLabelHyphenAtBoundary indicate domain part contain label starts or ends with hyphen.
+var ErrDomainLabelHyphenAtBoundary = newCodedError(ErrorCodeDomainLabelHyphenAtBoundary, "domain label starts or ends with hyphen")
+
+// ErrDomainNumericTLD indicate top-level domain of domain part is all-numeric.
+var ErrDomainNumericTLD = newCodedError(ErrorCodeDomainNumericTLD, "top-level domain is all-numeric")
+
+// ErrLocalPartTooLong indicate checked local part is longer than the limit.
+var ErrLocalPartTooLong = newCodedError(ErrorCodeLocalPartTooLong, "local part is too long")
+
+// ErrPathTooLong indicate forward-path of checked address is longer than the limit.
+var ErrPathTooLong = newCodedError(ErrorCodePathTooLong, "path of given email address is too long")
+
+// ErrNoSubAddressSeparator indicate there is no sub-addressing character for the domain part.
+var ErrNoSubAddressSeparator = newCodedError(ErrorCodeNoSubAddressSeparator, "no sub-addressing character for domain part")
+
+// ErrMixedScriptLocalPart indicate local part mix characters of scripts not commonly used together.
+var ErrMixedScriptLocalPart = newCodedError(ErrorCodeMixedScriptLocalPart, "local part mix characters of different scripts")
+
+// ErrMixedScriptDomainLabel indicate domain label mix characters of scripts not commonly used together.
+var ErrMixedScriptDomainLabel = newCodedError(ErrorCodeMixedScriptDomainLabel, "domain label mix characters of different scripts")
 
 // ErrUnknownDomainCharacterCombination indicate unknown mix of characters in domain part.
 type ErrUnknownDomainCharacterCombination struct {
-	idnaDomain           bool
-	dnHasDecimal         bool
-	dnHasHex             bool
-	dnHasDot             bool
-	dnHasColon           bool
-	dnHasOtherCharacters bool
+	// Class is the kinds of characters found in domain part.
+	Class DomainCharacterClass
+
+	// Domain is the rejected domain part.
+	Domain string
+}
+
+func (e *ErrUnknownDomainCharacterCombination) Error() string {
+	return "[ErrUnknownDomainCharacterCombination:" + e.Class.String() + "]"
+}
+
+// ErrorCode return machine-readable code of this error.
+func (e *ErrUnknownDomainCharacterCombination) ErrorCode() string {
+	return ErrorCodeUnknownDomainCharacterCombination
+}
+
+// ErrRejectedIPAddressClass indicate IP address literal belongs to a class
+// rejected by RejectIPAddressClasses option.
+type ErrRejectedIPAddressClass struct {
+	// Class is the rejected classes the address belongs to.
+	Class IPAddressClass
+
+	// Address is the rejected address literal.
+	Address string
+}
+
+func (e *ErrRejectedIPAddressClass) Error() string {
+	return "[ErrRejectedIPAddressClass: " + e.Address + ": " + e.Class.String() + "]"
+}
+
+// ErrorCode return machine-readable code of this error.
+func (e *ErrRejectedIPAddressClass) ErrorCode() string {
+	return ErrorCodeRejectedIPAddressClass
+}
+
+// GrammarError indicate given email address violate a rule of RFC 5321
+// Mailbox grammar in StrictRFC mode.
+type GrammarError struct {
+	// Rule is the name of violated grammar rule (eg: GrammarRuleDotString).
+	Rule string
+
+	// Err is ErrStrictRFCViolation, wrapped with ErrOffendingCharacter
+	// if ReportCharacterPosition option is set.
+	Err error
 }
 
-func (e *ErrUnknownDomainCharacterCombination) Error() (result string) {
-	result = "[ErrUnknownDomainCharacterCombination:"
-	if e.idnaDomain {
-		result += "I"
+func (e *GrammarError) Error() string {
+	return "[GrammarError: " + e.Rule + ": " + e.Err.Error() + "]"
+}
+
+// Unwrap return the wrapped violation.
+func (e *GrammarError) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidIDNADomain indicate domain part cannot be converted with IDNA rules.
+type ErrInvalidIDNADomain struct {
+	// Domain is the rejected domain part.
+	Domain string
+
+	// Err is the error from IDNA conversion.
+	Err error
+}
+
+func (e *ErrInvalidIDNADomain) Error() string {
+	return "[ErrInvalidIDNADomain: " + e.Domain + ": " + e.Err.Error() + "]"
+}
+
+// Unwrap return the error from IDNA conversion.
+func (e *ErrInvalidIDNADomain) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode return machine-readable code of this error.
+func (e *ErrInvalidIDNADomain) ErrorCode() string {
+	return ErrorCodeInvalidIDNADomain
+}
+
+// ErrMultipleViolations contain all violations found in given email address.
+type ErrMultipleViolations struct {
+	Errors []error
+}
+
+func (e *ErrMultipleViolations) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
 	}
-	if e.dnHasDecimal {
-		result += "3"
+	return "[ErrMultipleViolations: " + strings.Join(msgs, "; ") + "]"
+}
+
+// Unwrap return the contained violations.
+func (e *ErrMultipleViolations) Unwrap() []error {
+	return e.Errors
+}
+
+// Is report whether any of the contained violations matches `target`.
+func (e *ErrMultipleViolations) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As find the first contained violation matches `target`.
+func (e *ErrMultipleViolations) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
 	}
-	if e.dnHasHex {
-		result += "X"
+	return false
+}
+
+// ErrOffendingCharacter locate the character caused the wrapped violation.
+type ErrOffendingCharacter struct {
+	// Err is the sentinel error of violation.
+	Err error
+
+	// RuneOffset is the offset of offending character in runes of given address.
+	RuneOffset int
+
+	// ByteOffset is the offset of offending character in bytes of given address.
+	ByteOffset int
+
+	// Character is the offending character.
+	Character rune
+}
+
+func (e *ErrOffendingCharacter) Error() string {
+	return e.Err.Error() + ": " + strconv.QuoteRune(e.Character) + " at offset " + strconv.Itoa(e.RuneOffset)
+}
+
+// Unwrap return the sentinel error of violation.
+func (e *ErrOffendingCharacter) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode return machine-readable code of given error.
+// For ErrMultipleViolations the code of first violation is returned, use
+// ErrorCodes() to get codes of all violations.
+// Empty string will be returned if `err` is not an error of this package.
+func ErrorCode(err error) string {
+	var coder interface {
+		ErrorCode() string
 	}
-	if e.dnHasDot {
-		result += "."
+	if errors.As(err, &coder) {
+		return coder.ErrorCode()
 	}
-	if e.dnHasColon {
-		result += ":"
+	return ""
+}
+
+// ErrorCodes return machine-readable codes of given error.
+// Codes of all violations are returned for ErrMultipleViolations.
+func ErrorCodes(err error) (codes []string) {
+	var violations *ErrMultipleViolations
+	if !errors.As(err, &violations) {
+		if code := ErrorCode(err); code != "" {
+			codes = append(codes, code)
+		}
+		return
 	}
-	if e.dnHasOtherCharacters {
-		result += "C"
+	for _, e := range violations.Errors {
+		if code := ErrorCode(e); code != "" {
+			codes = append(codes, code)
+		}
 	}
-	result += "]"
 	return
 }
+
+// isWideCharacter check if given character `ch` occupy 2 columns on terminal.
+func isWideCharacter(ch rune) bool {
+	return ((ch >= 0x1100) && (ch <= 0x115F)) ||
+		((ch >= 0x2E80) && (ch <= 0xA4CF)) ||
+		((ch >= 0xAC00) && (ch <= 0xD7A3)) ||
+		((ch >= 0xF900) && (ch <= 0xFAFF)) ||
+		((ch >= 0xFE30) && (ch <= 0xFE4F)) ||
+		((ch >= 0xFF00) && (ch <= 0xFF60)) ||
+		((ch >= 0xFFE0) && (ch <= 0xFFE6)) ||
+		((ch >= 0x20000) && (ch <= 0x3FFFD))
+}
+
+// caretLine return a line which have caret placed under the character at
+// `runeOffset` of `emailAddress`.
+func caretLine(emailAddress []rune, runeOffset int) string {
+	var b strings.Builder
+	for idx, ch := range emailAddress {
+		if idx >= runeOffset {
+			break
+		}
+		if ch == '\t' {
+			b.WriteRune('\t')
+		} else if isWideCharacter(ch) {
+			b.WriteString("  ")
+		} else if unicode.IsPrint(ch) {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteRune('^')
+	return b.String()
+}
+
+// FormatDiagnostic render given email address with a caret placed under each
+// offending character carried by `err`.
+// Empty string will be returned if `err` does not carry any offending character.
+func FormatDiagnostic(emailAddress string, err error) string {
+	var errs []error
+	var violations *ErrMultipleViolations
+	if errors.As(err, &violations) {
+		errs = violations.Errors
+	} else {
+		errs = []error{err}
+	}
+	aux := ([]rune)(emailAddress)
+	var b strings.Builder
+	for _, e := range errs {
+		var offendingChar *ErrOffendingCharacter
+		if !errors.As(e, &offendingChar) {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(emailAddress)
+		}
+		b.WriteRune('\n')
+		b.WriteString(caretLine(aux, offendingChar.RuneOffset))
+		b.WriteRune(' ')
+		b.WriteString(offendingChar.Err.Error())
+	}
+	return b.String()
+}
@@ -1,3 +1,8 @@
 module github.com/yinyin/go-email-address-normalize
 
-go 1.13
+go 1.18
+
+require (
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.13.0
+)
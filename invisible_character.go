@@ -0,0 +1,70 @@
+package emailaddressnormalize
+
+import (
+	"unicode"
+)
+
+// isBidiControl check if given character `ch` is a bidirectional control character.
+func isBidiControl(ch rune) bool {
+	return (ch == 0x061C) ||
+		(ch == 0x200E) || (ch == 0x200F) ||
+		((ch >= 0x202A) && (ch <= 0x202E)) ||
+		((ch >= 0x2066) && (ch <= 0x2069))
+}
+
+// invisibleCharacterRecord keep the first position of each class of invisible characters.
+type invisibleCharacterRecord struct {
+	hasControlCharacter bool
+	hasFormatCharacter  bool
+	hasBidiControl      bool
+
+	controlCharacterAt characterPosition
+	formatCharacterAt  characterPosition
+	bidiControlAt      characterPosition
+}
+
+// inspect classify given character and record its position if it is invisible.
+func (r *invisibleCharacterRecord) inspect(at characterPosition) {
+	ch := at.character
+	if ch <= 0x7E && ch >= 0x20 {
+		return
+	}
+	switch {
+	case isBidiControl(ch):
+		if !r.hasBidiControl {
+			r.hasBidiControl = true
+			r.bidiControlAt = at
+		}
+	case unicode.Is(unicode.Cf, ch):
+		if !r.hasFormatCharacter {
+			r.hasFormatCharacter = true
+			r.formatCharacterAt = at
+		}
+	case unicode.IsControl(ch):
+		if !r.hasControlCharacter {
+			r.hasControlCharacter = true
+			r.controlCharacterAt = at
+		}
+	}
+}
+
+// checkInvisibleCharacters report invisible characters found in given address.
+func (n *normalizeInstance) checkInvisibleCharacters(opt *NormalizeOption, c *violationCollector) (shouldStop bool) {
+	r := &n.invisibleCharacters
+	if opt.RejectControlCharacters && r.hasControlCharacter {
+		if c.report(violationAt(opt, ErrGivenAddressContainControlCharacter, r.controlCharacterAt)) {
+			return true
+		}
+	}
+	if opt.RejectFormatCharacters && r.hasFormatCharacter {
+		if c.report(violationAt(opt, ErrGivenAddressContainFormatCharacter, r.formatCharacterAt)) {
+			return true
+		}
+	}
+	if opt.RejectBidiControls && r.hasBidiControl {
+		if c.report(violationAt(opt, ErrGivenAddressContainBidiControl, r.bidiControlAt)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package emailaddressnormalize
+
+import (
+	"net"
+	"strings"
+)
+
+// IPAddressClass represent the kinds of special-purpose ranges an IP address
+// literal belongs to.
+type IPAddressClass uint8
+
+// Kinds of special-purpose ranges in IPAddressClass.
+const (
+	IPAddressLoopback IPAddressClass = 1 << iota
+	IPAddressPrivate
+	IPAddressLinkLocal
+	IPAddressDocumentation
+	IPAddressMulticast
+	IPAddressUnspecified
+)
+
+func mustParseCIDRs(cidrs ...string) (result []*net.IPNet) {
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if nil != err {
+			panic(err)
+		}
+		result = append(result, ipNet)
+	}
+	return
+}
+
+// privateIPNets are RFC 1918 private IPv4 ranges and RFC 4193 unique local IPv6 range.
+var privateIPNets = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+// documentationIPNets are RFC 5737, RFC 3849 and RFC 9637 documentation ranges.
+var documentationIPNets = mustParseCIDRs("192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32", "3fff::/20")
+
+func ipNetsContain(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIPv4CompatibleAddress check if given address `ip` is a deprecated
+// IPv4-compatible IPv6 address (::a.b.c.d) other than :: and ::1.
+func isIPv4CompatibleAddress(ip net.IP) bool {
+	if len(ip) != net.IPv6len {
+		return false
+	}
+	for _, b := range ip[:12] {
+		if b != 0 {
+			return false
+		}
+	}
+	return !(ip.IsUnspecified() || ip.IsLoopback())
+}
+
+// classifyIPAddress return the special-purpose ranges given address `ip` belongs to.
+// IPv4-mapped IPv6 address is classified as its IPv4 address. IPv4-compatible
+// IPv6 address is classified as its embedded IPv4 address as well.
+func classifyIPAddress(ip net.IP) (c IPAddressClass) {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	} else if isIPv4CompatibleAddress(ip) {
+		c = classifyIPAddress(net.IP(ip[12:]))
+	}
+	if ip.IsLoopback() {
+		c |= IPAddressLoopback
+	}
+	if ipNetsContain(privateIPNets, ip) {
+		c |= IPAddressPrivate
+	}
+	if ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		c |= IPAddressLinkLocal
+	}
+	if ipNetsContain(documentationIPNets, ip) {
+		c |= IPAddressDocumentation
+	}
+	if ip.IsMulticast() {
+		c |= IPAddressMulticast
+	}
+	if ip.IsUnspecified() {
+		c |= IPAddressUnspecified
+	}
+	return
+}
+
+// IsLoopback check if address is a loopback address.
+func (c IPAddressClass) IsLoopback() bool {
+	return (c & IPAddressLoopback) != 0
+}
+
+// IsPrivate check if address is in RFC 1918 private or RFC 4193 unique local range.
+func (c IPAddressClass) IsPrivate() bool {
+	return (c & IPAddressPrivate) != 0
+}
+
+// IsLinkLocal check if address is a link-local address.
+func (c IPAddressClass) IsLinkLocal() bool {
+	return (c & IPAddressLinkLocal) != 0
+}
+
+// IsDocumentation check if address is in documentation range.
+func (c IPAddressClass) IsDocumentation() bool {
+	return (c & IPAddressDocumentation) != 0
+}
+
+// IsMulticast check if address is a multicast address.
+func (c IPAddressClass) IsMulticast() bool {
+	return (c & IPAddressMulticast) != 0
+}
+
+// IsUnspecified check if address is the unspecified address (0.0.0.0 or ::).
+func (c IPAddressClass) IsUnspecified() bool {
+	return (c & IPAddressUnspecified) != 0
+}
+
+// String return names of the classes joined with "|".
+func (c IPAddressClass) String() string {
+	names := make([]string, 0, 6)
+	if c.IsLoopback() {
+		names = append(names, "loopback")
+	}
+	if c.IsPrivate() {
+		names = append(names, "private")
+	}
+	if c.IsLinkLocal() {
+		names = append(names, "link-local")
+	}
+	if c.IsDocumentation() {
+		names = append(names, "documentation")
+	}
+	if c.IsMulticast() {
+		names = append(names, "multicast")
+	}
+	if c.IsUnspecified() {
+		names = append(names, "unspecified")
+	}
+	return strings.Join(names, "|")
+}
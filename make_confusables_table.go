@@ -0,0 +1,104 @@
+//go:build ignore
+
+// Generator of confusables_table.go from UTS #39 confusables.txt.
+// Run with `go generate` to refresh the table with latest Unicode data.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var sourceURL = flag.String("url", "https://www.unicode.org/Public/security/latest/confusables.txt", "URL of confusables.txt")
+var localFile = flag.String("local", "", "path of local confusables.txt, used instead of downloading")
+var outputFile = flag.String("output", "confusables_table.go", "path of generated Go source")
+
+func openSource() io.ReadCloser {
+	if *localFile != "" {
+		fp, err := os.Open(*localFile)
+		if nil != err {
+			log.Fatalf("cannot open %s: %v", *localFile, err)
+		}
+		return fp
+	}
+	resp, err := http.Get(*sourceURL)
+	if nil != err {
+		log.Fatalf("cannot download %s: %v", *sourceURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("cannot download %s: %s", *sourceURL, resp.Status)
+	}
+	return resp.Body
+}
+
+func parseCodePoints(text string) (result []rune) {
+	for _, field := range strings.Fields(text) {
+		v, err := strconv.ParseUint(field, 16, 32)
+		if nil != err {
+			log.Fatalf("invalid code point %q: %v", field, err)
+		}
+		result = append(result, rune(v))
+	}
+	return
+}
+
+func main() {
+	flag.Parse()
+	src := openSource()
+	defer src.Close()
+	var b strings.Builder
+	b.WriteString("// Code generated by make_confusables_table.go; DO NOT EDIT.\n\n")
+	b.WriteString("package emailaddressnormalize\n\n")
+	b.WriteString("// Header of source confusables.txt:\n//\n")
+	inHeader := true
+	entries := 0
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "\uFEFF")
+		if strings.HasPrefix(line, "#") {
+			if inHeader {
+				b.WriteString("//" + line[1:] + "\n")
+			}
+			continue
+		}
+		if inHeader {
+			inHeader = false
+			b.WriteString("\n// confusablesTable map characters into their prototypes.\n")
+			b.WriteString("var confusablesTable = map[rune]string{\n")
+		}
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source := parseCodePoints(fields[0])
+		if len(source) != 1 {
+			log.Fatalf("unexpected source code points: %q", line)
+		}
+		fmt.Fprintf(&b, "0x%04X: %+q,\n", source[0], string(parseCodePoints(fields[1])))
+		entries++
+	}
+	if err := scanner.Err(); nil != err {
+		log.Fatalf("cannot read source: %v", err)
+	}
+	b.WriteString("}\n")
+	formatted, err := format.Source([]byte(b.String()))
+	if nil != err {
+		log.Fatalf("cannot format generated source: %v", err)
+	}
+	if err = os.WriteFile(*outputFile, formatted, 0644); nil != err {
+		log.Fatalf("cannot write %s: %v", *outputFile, err)
+	}
+	log.Printf("%d entries written into %s", entries, *outputFile)
+}
@@ -1,10 +1,13 @@
 package emailaddressnormalize
 
 import (
+	"net"
 	"unicode"
 )
 
 const domainLengthLimit = 255
+const localPartLengthLimit = 64
+const pathLengthLimit = 256
 
 var charactersNeedQuote = ([]rune)("\"(),:;<>@[\\]")
 var charactersNotVerySafe = ([]rune)("%|!#$*/\\")
@@ -27,6 +30,18 @@ func isNotVerySafeCharacter(ch rune) bool {
 	return false
 }
 
+// foldFullWidthCharacter map full-width ASCII variants (U+FF01 - U+FF5E) and
+// ideographic space (U+3000) into ASCII characters.
+func foldFullWidthCharacter(ch rune) rune {
+	if (ch >= 0xFF01) && (ch <= 0xFF5E) {
+		return ch - 0xFEE0
+	}
+	if ch == 0x3000 {
+		return ' '
+	}
+	return ch
+}
+
 func runesIndexRune(s []rune, ch rune) int {
 	for idx, elem := range s {
 		if elem == ch {
@@ -38,37 +53,136 @@ func runesIndexRune(s []rune, ch rune) int {
 
 type normalizeStateCallable func(ch rune) (nextState normalizeStateCallable)
 
+// characterPosition locate a character in given email address.
+type characterPosition struct {
+	runeOffset int
+	byteOffset int
+	character  rune
+}
+
+// commentCollector keep comments (RFC 5322 CFWS) dropped from given address.
+type commentCollector struct {
+	comments    []string
+	commentText []rune
+
+	// depth is the nesting level of comment, 0 when not in comment.
+	depth    int
+	openedAt characterPosition
+
+	hasStrayClose bool
+	strayCloseAt  characterPosition
+}
+
+// openComment start a comment, or a nested comment when already in comment.
+func (c *commentCollector) openComment(at characterPosition) {
+	if c.depth == 0 {
+		c.openedAt = at
+	} else {
+		c.commitToComment('(')
+	}
+	c.depth++
+}
+
+// markStrayClose record a close parenthesis found outside of comment.
+func (c *commentCollector) markStrayClose(at characterPosition) {
+	if !c.hasStrayClose {
+		c.hasStrayClose = true
+		c.strayCloseAt = at
+	}
+}
+
+// unbalancedParenthesis return true and position of offending parenthesis
+// if there is stray close parenthesis or unclosed comment.
+func (c *commentCollector) unbalancedParenthesis() (unbalanced bool, at characterPosition) {
+	if c.hasStrayClose {
+		return true, c.strayCloseAt
+	}
+	if c.depth > 0 {
+		return true, c.openedAt
+	}
+	return false, at
+}
+
+// commitToComment append given character `ch` into current comment text.
+func (c *commentCollector) commitToComment(ch rune) {
+	c.commentText = append(c.commentText, ch)
+}
+
+// closeComment end current comment. Return true and move comment text into
+// collected comments when the outermost comment is closed.
+func (c *commentCollector) closeComment() (closed bool) {
+	c.depth--
+	if c.depth > 0 {
+		c.commitToComment(')')
+		return false
+	}
+	c.comments = append(c.comments, string(c.commentText))
+	c.commentText = c.commentText[:0]
+	return true
+}
+
 type normalizeLocalPartInstance struct {
 	localPart             []rune
 	lastCommitedCharacter rune
 
-	needQuote bool
+	// localPartPositions and localPartQuoted are parallel to localPart and
+	// keep input position and quoting state of each committed character.
+	localPartPositions []characterPosition
+	localPartQuoted    []bool
+	inQuotedString     bool
+
+	needQuote     bool
+	quotedInInput bool
+	preserveCase  bool
+
+	commentCollector
 
 	stateCallable normalizeStateCallable
 	shouldStop    bool
 
 	hasUnsafeCharacter   bool
 	hasNonASCIICharacter bool
+
+	inputPosition        characterPosition
+	lastCommitedPosition characterPosition
+	needQuoteAt          characterPosition
+	unsafeCharacterAt    characterPosition
+	nonASCIICharacterAt  characterPosition
+}
+
+// markNeedQuote flag local part have to be quoted because of character at given position.
+func (n *normalizeLocalPartInstance) markNeedQuote(at characterPosition) {
+	if !n.needQuote {
+		n.needQuote = true
+		n.needQuoteAt = at
+	}
 }
 
 // commitToLocalPart append given character `ch` into normalized local part.
 func (n *normalizeLocalPartInstance) commitToLocalPart(ch rune) {
 	if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
-		ch = unicode.ToLower(ch)
+		if !n.preserveCase {
+			ch = unicode.ToLower(ch)
+		}
 	} else if !unicode.IsPrint(ch) {
 		return // skip non-printables.
 	} else if unicode.IsSpace(ch) || isNeedQuote(ch) {
-		n.needQuote = true
+		n.markNeedQuote(n.inputPosition)
 	} else if (ch == '.') && (n.lastCommitedCharacter == '.') {
-		n.needQuote = true
-	} else if isNotVerySafeCharacter(ch) {
+		n.markNeedQuote(n.inputPosition)
+	} else if isNotVerySafeCharacter(ch) && !n.hasUnsafeCharacter {
 		n.hasUnsafeCharacter = true
+		n.unsafeCharacterAt = n.inputPosition
 	}
-	if ch > unicode.MaxASCII {
+	if (ch > unicode.MaxASCII) && !n.hasNonASCIICharacter {
 		n.hasNonASCIICharacter = true
+		n.nonASCIICharacterAt = n.inputPosition
 	}
 	n.localPart = append(n.localPart, ch)
+	n.localPartPositions = append(n.localPartPositions, n.inputPosition)
+	n.localPartQuoted = append(n.localPartQuoted, n.inQuotedString)
 	n.lastCommitedCharacter = ch
+	n.lastCommitedPosition = n.inputPosition
 }
 
 func (n *normalizeLocalPartInstance) stateQuotedLocalPartInEscape(ch rune) (nextState normalizeStateCallable) {
@@ -79,6 +193,7 @@ func (n *normalizeLocalPartInstance) stateQuotedLocalPartInEscape(ch rune) (next
 func (n *normalizeLocalPartInstance) stateQuotedLocalPart(ch rune) (nextState normalizeStateCallable) {
 	switch ch {
 	case '"':
+		n.inQuotedString = false
 		return n.stateSimpleLocalPart
 	case '\\':
 		return n.stateQuotedLocalPartInEscape
@@ -88,26 +203,23 @@ func (n *normalizeLocalPartInstance) stateQuotedLocalPart(ch rune) (nextState no
 	return nil
 }
 
-func (n *normalizeLocalPartInstance) stateLocalPartCommentQuotedInEscape(ch rune) (nextState normalizeStateCallable) {
-	return n.stateLocalPartCommentQuotedText
-}
-
-func (n *normalizeLocalPartInstance) stateLocalPartCommentQuotedText(ch rune) (nextState normalizeStateCallable) {
-	switch ch {
-	case '"':
-		return n.stateLocalPartComment
-	case '\\':
-		return n.stateLocalPartCommentQuotedInEscape
-	}
-	return nil
+func (n *normalizeLocalPartInstance) stateLocalPartCommentInEscape(ch rune) (nextState normalizeStateCallable) {
+	n.commitToComment(ch)
+	return n.stateLocalPartComment
 }
 
 func (n *normalizeLocalPartInstance) stateLocalPartComment(ch rune) (nextState normalizeStateCallable) {
 	switch ch {
-	case '"':
-		return n.stateLocalPartCommentQuotedText
+	case '\\':
+		return n.stateLocalPartCommentInEscape
+	case '(':
+		n.openComment(n.inputPosition)
 	case ')':
-		return n.stateSimpleLocalPart
+		if n.closeComment() {
+			return n.stateSimpleLocalPart
+		}
+	default:
+		n.commitToComment(ch)
 	}
 	return nil
 }
@@ -119,7 +231,10 @@ func (n *normalizeLocalPartInstance) stateSimpleLocalPart(ch rune) (nextState no
 		n.shouldStop = true
 		return n.stateStart
 	case '(':
+		n.openComment(n.inputPosition)
 		return n.stateLocalPartComment
+	case ')':
+		n.markStrayClose(n.inputPosition)
 	default:
 		n.commitToLocalPart(ch)
 	}
@@ -129,15 +244,21 @@ func (n *normalizeLocalPartInstance) stateSimpleLocalPart(ch rune) (nextState no
 func (n *normalizeLocalPartInstance) stateStart(ch rune) (nextState normalizeStateCallable) {
 	switch ch {
 	case '"':
+		n.quotedInInput = true
+		n.inQuotedString = true
 		return n.stateQuotedLocalPart
 	case '(':
+		n.openComment(n.inputPosition)
 		return n.stateLocalPartComment
+	case ')':
+		n.markStrayClose(n.inputPosition)
+		return n.stateSimpleLocalPart
 	case '.':
-		n.needQuote = true
+		n.markNeedQuote(n.inputPosition)
 		n.commitToLocalPart(ch)
 		return n.stateSimpleLocalPart
 	case '@':
-		n.needQuote = true
+		n.markNeedQuote(n.inputPosition)
 		n.shouldStop = true
 		return n.stateStart
 	default:
@@ -146,10 +267,27 @@ func (n *normalizeLocalPartInstance) stateStart(ch rune) (nextState normalizeSta
 	}
 }
 
+// subAddressOffset find the offset of sub-address separator in local part.
+// Separators at the beginning of local part or in quoted string are ignored.
+// Return -1 if there is no separator.
+func (n *normalizeLocalPartInstance) subAddressOffset(subAddrChars []rune, lastOccurrence bool) (offset int) {
+	offset = -1
+	for idx, ch := range n.localPart {
+		if (idx == 0) || n.localPartQuoted[idx] || (runesIndexRune(subAddrChars, ch) < 0) {
+			continue
+		}
+		if !lastOccurrence {
+			return idx
+		}
+		offset = idx
+	}
+	return
+}
+
 // stopCheck perform check for stopping normalize process.
 func (n *normalizeLocalPartInstance) stopCheck() {
 	if n.lastCommitedCharacter == '.' {
-		n.needQuote = true
+		n.markNeedQuote(n.lastCommitedPosition)
 	}
 }
 
@@ -181,34 +319,90 @@ func (n *normalizeLocalPartInstance) resultLocalPart() string {
 	return string(buf)
 }
 
+// requoteLocalPart check given unquoted local part and quote it if necessary.
+// Letters are lowercased unless `preserveCase` is set.
+func requoteLocalPart(localPart []rune, preserveCase bool) string {
+	n := normalizeLocalPartInstance{
+		localPart:    make([]rune, 0, len(localPart)),
+		preserveCase: preserveCase,
+	}
+	if (len(localPart) > 0) && (localPart[0] == '.') {
+		n.markNeedQuote(n.inputPosition)
+	}
+	for _, ch := range localPart {
+		n.commitToLocalPart(ch)
+	}
+	n.stopCheck()
+	return n.resultLocalPart()
+}
+
 type normalizeInstance struct {
-	emailAddress []rune
+	emailAddressText string
+	emailAddress     []rune
+	inputOffset      int
+	inputByteOffset  int
+
+	foldFullWidth bool
+
+	invisibleCharacters invisibleCharacterRecord
 
 	localPartNormalizer normalizeLocalPartInstance
+	localPartEndOffset  int
 	domainPart          []rune
+	domainComments      commentCollector
 
 	lastCommitedCharacter rune
 
-	subaddressOffsets    [16]int
-	idnaDomain           bool
-	dnHasDecimal         bool
-	dnHasHex             bool
-	dnHasDot             bool
-	dnHasColon           bool
-	dnHasOtherCharacters bool
+	subAddressOffset int
+	dnClass          DomainCharacterClass
 
 	checkedIsIPLiteralPositive bool
+
+	hasInvalidDomainCharacter bool
+	invalidDomainCharacterAt  characterPosition
+	hasEmptyDomainLabel       bool
+	emptyDomainLabelAt        characterPosition
+	hasPendingDomainSpace     bool
+	pendingDomainSpaceAt      characterPosition
+
+	// addressLiteral keep content of bracketed address literal as given.
+	addressLiteral          []rune
+	hasAddressLiteral       bool
+	addressLiteralClosed    bool
+	addressLiteralMalformed bool
+	addressLiteralAt        characterPosition
+	addressLiteralTag       string
+	addressLiteralIP        net.IP
+	ipAddressClass          IPAddressClass
+
+	domainALabel string
+	domainULabel string
+
+	normalizedDomainPart string
+	subdomainMailbox     string
+
+	localPartMixedScript bool
+	domainMixedScript    bool
 }
 
-func newNormalizeInstance(emailAddress string) (instance *normalizeInstance) {
+func newNormalizeInstance(emailAddress string, opt *NormalizeOption) (instance *normalizeInstance) {
 	aux := ([]rune)(emailAddress)
 	l := len(aux)
+	bufCap := l - 1
+	if bufCap < 0 {
+		bufCap = 0
+	}
 	instance = &normalizeInstance{
-		emailAddress: aux,
+		emailAddressText:   emailAddress,
+		foldFullWidth:      !opt.KeepFullWidthCharacters,
+		emailAddress:       aux,
+		localPartEndOffset: l,
+		subAddressOffset:   -1,
 		localPartNormalizer: normalizeLocalPartInstance{
-			localPart: make([]rune, 0, l-1),
+			localPart:    make([]rune, 0, bufCap),
+			preserveCase: opt.PreserveLocalPartCase,
 		},
-		domainPart: make([]rune, 0, l-1),
+		domainPart: make([]rune, 0, bufCap),
 	}
 	return
 }
@@ -220,7 +414,15 @@ func (n *normalizeInstance) runNormalize() (err error) {
 		return
 	}
 	stateCallable := n.stateLocalPart
-	for _, ch := range n.emailAddress {
+	runeOffset := 0
+	for byteOffset, ch := range n.emailAddressText {
+		n.inputOffset = runeOffset
+		n.inputByteOffset = byteOffset
+		runeOffset++
+		n.invisibleCharacters.inspect(n.currentPosition(ch))
+		if n.foldFullWidth {
+			ch = foldFullWidthCharacter(ch)
+		}
 		if nextStateCallable := stateCallable(ch); nil != nextStateCallable {
 			stateCallable = nextStateCallable
 		}
@@ -228,20 +430,36 @@ func (n *normalizeInstance) runNormalize() (err error) {
 	return
 }
 
+// currentPosition return position of given character `ch` at current input offset.
+func (n *normalizeInstance) currentPosition(ch rune) characterPosition {
+	return characterPosition{
+		runeOffset: n.inputOffset,
+		byteOffset: n.inputByteOffset,
+		character:  ch,
+	}
+}
+
 // commitToDomainPart append guven character `ch` into normalized domain part.
+//
+// Characters which cannot be part of domain and empty labels are not
+// committed but recorded for checkDomainSyntax() to report.
 func (n *normalizeInstance) commitToDomainPart(ch rune) {
-	if len(n.domainPart) >= domainLengthLimit {
+	if unicode.IsSpace(ch) {
+		// spaces around domain (FWS of CFWS) are skipped, spaces within domain are recorded once committed character follows.
+		if (len(n.domainPart) > 0) && !n.hasPendingDomainSpace {
+			n.hasPendingDomainSpace = true
+			n.pendingDomainSpaceAt = n.currentPosition(ch)
+		}
 		return
 	}
-	if unicode.IsSpace(ch) || (!unicode.IsPrint(ch)) {
-		return // skip spaces and non-printables.
+	if !unicode.IsPrint(ch) {
+		return // skip non-printables, reported by invisible character check.
+	}
+	if (ch == 0x3002) || (ch == 0xFF0E) || (ch == 0xFF61) {
+		ch = '.'
 	}
 	if ch > unicode.MaxASCII {
-		if (ch == 0x3002) || (ch == 0xFF0E) || (ch == 0xFF61) {
-			ch = '.'
-		} else {
-			n.idnaDomain = true
-		}
+		n.dnClass |= DomainCharacterIDNA
 		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
 			ch = unicode.ToLower(ch)
 		}
@@ -250,156 +468,375 @@ func (n *normalizeInstance) commitToDomainPart(ch rune) {
 	} else if (ch == '-') || (ch == ':') {
 	} else if ch == '.' {
 		if (n.lastCommitedCharacter == '.') || (0 == len(n.domainPart)) {
+			n.recordEmptyDomainLabel(n.currentPosition(ch))
 			return
 		}
 	} else {
-		return // skip non-(Letter, Digit, Hyphen) ASCII characters.
+		n.recordInvalidDomainCharacter(n.currentPosition(ch))
+		return
+	}
+	if n.hasPendingDomainSpace {
+		n.hasPendingDomainSpace = false
+		n.recordInvalidDomainCharacter(n.pendingDomainSpaceAt)
 	}
 	switch {
 	case (ch >= '0') && (ch <= '9'):
-		n.dnHasDecimal = true
+		n.dnClass |= DomainCharacterDecimal
 	case (ch >= 'a') && (ch <= 'f'):
-		n.dnHasHex = true
+		n.dnClass |= DomainCharacterHex
 	case ch == '.':
-		n.dnHasDot = true
+		n.dnClass |= DomainCharacterDot
 	case ch == ':':
-		n.dnHasColon = true
+		n.dnClass |= DomainCharacterColon
 	default:
-		n.dnHasOtherCharacters = true
+		n.dnClass |= DomainCharacterOther
 	}
 	n.domainPart = append(n.domainPart, ch)
 	n.lastCommitedCharacter = ch
 }
 
+// recordInvalidDomainCharacter keep position of first character which cannot
+// be part of domain.
+func (n *normalizeInstance) recordInvalidDomainCharacter(at characterPosition) {
+	if n.hasInvalidDomainCharacter && (n.invalidDomainCharacterAt.runeOffset <= at.runeOffset) {
+		return
+	}
+	n.hasInvalidDomainCharacter = true
+	n.invalidDomainCharacterAt = at
+}
+
+// recordEmptyDomainLabel keep position of the dot which starts first empty label.
+func (n *normalizeInstance) recordEmptyDomainLabel(at characterPosition) {
+	if n.hasEmptyDomainLabel {
+		return
+	}
+	n.hasEmptyDomainLabel = true
+	n.emptyDomainLabelAt = at
+}
+
 func (n *normalizeInstance) stateIPLiteralDomainPart(ch rune) (nextState normalizeStateCallable) {
 	if ch == ']' {
+		n.addressLiteralClosed = true
 		return n.stateSimpleDomainPart
 	}
-	n.commitToDomainPart(ch)
+	n.addressLiteral = append(n.addressLiteral, ch)
+	return nil
+}
+
+func (n *normalizeInstance) stateDomainPartCommentInEscape(ch rune) (nextState normalizeStateCallable) {
+	n.domainComments.commitToComment(ch)
+	return n.stateDomainPartComment
+}
+
+func (n *normalizeInstance) stateDomainPartComment(ch rune) (nextState normalizeStateCallable) {
+	switch ch {
+	case '\\':
+		return n.stateDomainPartCommentInEscape
+	case '(':
+		n.domainComments.openComment(n.currentPosition(ch))
+	case ')':
+		if n.domainComments.closeComment() {
+			return n.stateSimpleDomainPart
+		}
+	default:
+		n.domainComments.commitToComment(ch)
+	}
 	return nil
 }
 
 func (n *normalizeInstance) stateSimpleDomainPart(ch rune) (nextState normalizeStateCallable) {
-	if ch == '[' {
+	switch ch {
+	case '[':
+		if n.hasAddressLiteral || (len(n.domainPart) > 0) {
+			n.addressLiteralMalformed = true
+		}
+		if !n.hasAddressLiteral {
+			n.hasAddressLiteral = true
+			n.addressLiteralAt = n.currentPosition(ch)
+		}
 		return n.stateIPLiteralDomainPart
+	case '(':
+		n.domainComments.openComment(n.currentPosition(ch))
+		return n.stateDomainPartComment
+	case ')':
+		n.domainComments.markStrayClose(n.currentPosition(ch))
+		return nil
 	}
 	n.commitToDomainPart(ch)
 	return nil
 }
 
 func (n *normalizeInstance) stateLocalPart(ch rune) (nextState normalizeStateCallable) {
-	if (ch | 0xF) == 0x2F {
-		offsetIdx := ch & 0xF
-		if 0 == n.subaddressOffsets[offsetIdx] {
-			n.subaddressOffsets[offsetIdx] = len(n.localPartNormalizer.localPart)
-		}
-	}
+	n.localPartNormalizer.inputPosition = n.currentPosition(ch)
 	if shouldStop := n.localPartNormalizer.putCharacter(ch); shouldStop {
+		n.localPartEndOffset = n.inputOffset
 		return n.stateSimpleDomainPart
 	}
 	return nil
 }
 
-func (n *normalizeInstance) isIPLiteralDomain() (bool, error) {
-	if (n.idnaDomain || n.dnHasOtherCharacters) && (!n.dnHasColon) {
+// classifyBareDomainPart check if domain part without brackets is an IP
+// address. Domain part is replaced with canonical form of the IP address.
+func (n *normalizeInstance) classifyBareDomainPart(opt *NormalizeOption) (isIPLiteral bool, err error) {
+	if n.hasInvalidDomainCharacter || n.hasEmptyDomainLabel {
+		return false, nil // not an IP address, reported by checkDomainSyntax().
+	}
+	c := n.dnClass
+	domainPart := string(n.domainPart)
+	if opt.BareIPDomain != BareIPDomainAsDNSName {
+		if c.HasColon() {
+			canonicalAddr, ip, parseErr := parseIPv6AddressLiteral(domainPart, opt.AllowIPv4MappedAddressLiteral)
+			if nil == parseErr {
+				n.domainPart = ([]rune)(AddressLiteralTagIPv6 + ":" + canonicalAddr)
+				n.addressLiteralTag = AddressLiteralTagIPv6
+				n.addressLiteralIP = ip
+				return true, nil
+			}
+		} else if c.HasDecimal() && c.HasDot() && (!c.HasHex()) && (!c.IsIDNA()) && (!c.HasOtherCharacters()) {
+			if ip := net.ParseIP(domainPart); (ip != nil) && (ip.To4() != nil) {
+				n.domainPart = ([]rune)(ip.To4().String())
+				n.addressLiteralIP = ip
+				return true, nil
+			}
+		} else {
+			return false, nil
+		}
+	} else if !c.HasColon() {
 		return false, nil
 	}
-	if n.dnHasDecimal && n.dnHasDot && (!n.dnHasHex) && (!n.dnHasColon) {
-		return true, nil
+	err = &ErrUnknownDomainCharacterCombination{
+		Class:  c,
+		Domain: domainPart,
 	}
-	if (n.dnHasDecimal || n.dnHasHex) && n.dnHasColon && (!n.dnHasDot) {
-		return true, nil
+	return false, err
+}
+
+// violationCollector keep violations found in check process.
+type violationCollector struct {
+	collectAll bool
+	violations []error
+}
+
+// report record given violation `err` and return true if check process should stop.
+func (c *violationCollector) report(err error) (shouldStop bool) {
+	c.violations = append(c.violations, err)
+	return !c.collectAll
+}
+
+// result return the error to report for collected violations.
+func (c *violationCollector) result() (err error) {
+	if len(c.violations) == 0 {
+		return nil
 	}
-	err := &ErrUnknownDomainCharacterCombination{
-		idnaDomain:           n.idnaDomain,
-		dnHasDecimal:         n.dnHasDecimal,
-		dnHasHex:             n.dnHasHex,
-		dnHasDot:             n.dnHasDot,
-		dnHasColon:           n.dnHasColon,
-		dnHasOtherCharacters: n.dnHasOtherCharacters,
+	if !c.collectAll {
+		return c.violations[0]
+	}
+	return &ErrMultipleViolations{
+		Errors: c.violations,
+	}
+}
+
+// violationAt return given sentinel `err` with position of offending character
+// attached if ReportCharacterPosition option is set.
+func violationAt(opt *NormalizeOption, err error, at characterPosition) error {
+	if !opt.ReportCharacterPosition {
+		return err
+	}
+	return &ErrOffendingCharacter{
+		Err:        err,
+		RuneOffset: at.runeOffset,
+		ByteOffset: at.byteOffset,
+		Character:  at.character,
 	}
-	return false, err
 }
 
 func (n *normalizeInstance) check(opt *NormalizeOption) (err error) {
-	if !opt.AllowIPLiteral {
-		var isIPLiteral bool
-		if isIPLiteral, err = n.isIPLiteralDomain(); nil != err {
+	c := violationCollector{
+		collectAll: opt.CollectAllViolations,
+	}
+	defer func() {
+		err = c.result()
+	}()
+	if opt.StrictRFC {
+		if grammarErr := n.checkStrictRFC(opt); nil != grammarErr {
+			if c.report(grammarErr) {
+				return
+			}
+		}
+	}
+	if n.checkInvisibleCharacters(opt, &c) {
+		return
+	}
+	if unbalanced, at := n.localPartNormalizer.unbalancedParenthesis(); unbalanced {
+		if c.report(violationAt(opt, ErrUnbalancedParenthesis, at)) {
 			return
-		} else if isIPLiteral {
-			err = ErrGivenAddressHasIPLiteral
+		}
+	}
+	if unbalanced, at := n.domainComments.unbalancedParenthesis(); unbalanced {
+		if c.report(violationAt(opt, ErrUnbalancedParenthesis, at)) {
 			return
 		}
-	} else if isIPLiteral, _ := n.isIPLiteralDomain(); isIPLiteral {
-		n.checkedIsIPLiteralPositive = true
+	}
+	var isIPLiteral bool
+	var classifyErr error
+	if n.hasAddressLiteral {
+		if classifyErr = n.checkAddressLiteral(opt); nil != classifyErr {
+			classifyErr = violationAt(opt, classifyErr, n.addressLiteralAt)
+		} else {
+			isIPLiteral = true
+		}
+	} else if isIPLiteral, classifyErr = n.classifyBareDomainPart(opt); isIPLiteral && (opt.BareIPDomain == BareIPDomainReject) {
+		isIPLiteral, classifyErr = false, ErrBareIPDomain
+	}
+	if nil != classifyErr {
+		if c.report(classifyErr) {
+			return
+		}
+	} else if isIPLiteral {
+		if !opt.AllowIPLiteral {
+			if c.report(ErrGivenAddressHasIPLiteral) {
+				return
+			}
+		} else {
+			n.checkedIsIPLiteralPositive = true
+			if classErr := n.checkIPAddressClass(opt); nil != classErr {
+				if c.report(classErr) {
+					return
+				}
+			}
+		}
 	}
 	if (!opt.AllowQuotedLocalPart) && n.localPartNormalizer.needQuote {
-		err = ErrGivenAddressNeedQuote
-		return
+		if c.report(violationAt(opt, ErrGivenAddressNeedQuote, n.localPartNormalizer.needQuoteAt)) {
+			return
+		}
 	}
 	if (!opt.AllowLocalPartSpecialChars) && n.localPartNormalizer.hasUnsafeCharacter {
-		err = ErrGivenAddressContainSpecialCharacter
-		return
+		if c.report(violationAt(opt, ErrGivenAddressContainSpecialCharacter, n.localPartNormalizer.unsafeCharacterAt)) {
+			return
+		}
 	}
 	if (!opt.AllowLocalPartInternationalChars) && n.localPartNormalizer.hasNonASCIICharacter {
-		err = ErrGivenAddressLocalPartContainI18NCharacter
-		return
+		if c.report(violationAt(opt, ErrGivenAddressLocalPartContainI18NCharacter, n.localPartNormalizer.nonASCIICharacterAt)) {
+			return
+		}
 	}
 	if len(n.domainPart) == 0 {
-		err = ErrEmptyDomainAfterCheck
-		return
+		if (!n.hasAddressLiteral) && c.report(ErrEmptyDomainAfterCheck) {
+			return
+		}
+	} else if (!isIPLiteral) && (nil == classifyErr) {
+		if opt.UnicodeNormalization && n.dnClass.IsIDNA() {
+			n.domainPart = ([]rune)(composeText(string(n.domainPart)))
+		}
+		if opt.DomainForm != DomainFormAsGiven {
+			if convertErr := n.convertDomainForm(opt.DomainForm); nil != convertErr {
+				if c.report(convertErr) {
+					return
+				}
+			}
+		}
+		if n.checkDomainSyntax(opt, &c) {
+			return
+		}
+	}
+	if opt.MixedScriptPolicy != MixedScriptIgnore {
+		if n.checkMixedScript(opt, &c) {
+			return
+		}
 	}
 	if len(n.localPartNormalizer.localPart) == 0 {
-		err = ErrEmptyLocalPartAfterCheck
-		return
+		if c.report(ErrEmptyLocalPartAfterCheck) {
+			return
+		}
+	}
+	checkedLocalPart := n.resultCheckedLocalPart(opt)
+	if limit := lengthLimit(opt.LocalPartLengthLimit, localPartLengthLimit); (limit > 0) && (len(checkedLocalPart) > limit) {
+		if c.report(ErrLocalPartTooLong) {
+			return
+		}
+	}
+	// forward-path = "<" Mailbox ">"
+	pathLength := len(checkedLocalPart) + len(n.resultDomainPart()) + 3
+	if limit := lengthLimit(opt.PathLengthLimit, pathLengthLimit); (limit > 0) && (pathLength > limit) {
+		if c.report(ErrPathTooLong) {
+			return
+		}
 	}
 	return
 }
 
-func (n *normalizeInstance) normalizeLocalPart(opt *NormalizeOption) (resultLocalPart string) {
+// lengthLimit return effective length limit from optional value `optionValue`.
+// Default limit `defaultValue` is used when `optionValue` is zero and negative
+// value (which disable the check) is returned when `optionValue` is negative.
+func lengthLimit(optionValue, defaultValue int) int {
+	if optionValue == 0 {
+		return defaultValue
+	}
+	return optionValue
+}
+
+// normalizeLocalPart return normalized local part and the sub-address removed from it.
+// CAUTION: **Must** invoke after `canonicalizeDomain()` method.
+func (n *normalizeInstance) normalizeLocalPart(opt *NormalizeOption) (resultLocalPart, removedSubAddress string) {
 	buf := n.localPartNormalizer.localPart
+	if n.subdomainMailbox != "" {
+		return requoteLocalPart(([]rune)(n.subdomainMailbox), false), string(buf)
+	}
 	if opt.RemoveSubAddressingWith != nil {
-		subAddrChars := opt.RemoveSubAddressingWith(string(n.domainPart))
-		for _, ch := range subAddrChars {
-			if (ch | 0xF) == 0x2F {
-				offsetIdx := ch & 0xF
-				if ofst := n.subaddressOffsets[offsetIdx]; (ofst > 0) && (ofst < len(buf)) {
-					buf = buf[:ofst]
-				}
-			} else if ofst := runesIndexRune(buf, ch); ofst >= 0 {
-				buf = buf[:ofst]
-			}
-
+		subAddrChars := opt.RemoveSubAddressingWith(n.normalizedDomainPart)
+		lastOccurrence := opt.SubAddressingOccurrence == SubAddressingLastOccurrence
+		if ofst := n.localPartNormalizer.subAddressOffset(subAddrChars, lastOccurrence); ofst > 0 {
+			n.subAddressOffset = ofst
+			buf = buf[:ofst]
+			removedSubAddress = string(n.localPartNormalizer.localPart[ofst:])
 		}
 	}
 	if len(buf) == 0 {
 		return
 	}
-	if opt.RemoveLocalPartDots {
-		n2 := normalizeLocalPartInstance{
-			localPart: make([]rune, 0, len(buf)),
+	if opt.UnicodeNormalization {
+		buf = ([]rune)(foldText(string(buf)))
+	}
+	var mapping map[rune]rune
+	if opt.MapLocalPartCharactersWith != nil {
+		mapping = opt.MapLocalPartCharactersWith(n.normalizedDomainPart)
+	}
+	removeDots := opt.shouldRemoveLocalPartDots(n.normalizedDomainPart)
+	preserveCase := n.localPartNormalizer.preserveCase
+	if (len(mapping) == 0) && (!removeDots) && (!preserveCase) && (!opt.UnicodeNormalization) && (len(n.localPartNormalizer.localPart) == len(buf)) {
+		resultLocalPart = n.localPartNormalizer.resultLocalPart()
+		return
+	}
+	mappedBuf := make([]rune, 0, len(buf))
+	for _, ch := range buf {
+		if preserveCase {
+			ch = unicode.ToLower(ch)
 		}
-		for _, ch := range buf {
-			if ch == '.' {
+		if targetCh, ok := mapping[ch]; ok {
+			if targetCh == DeleteCharacter {
 				continue
 			}
-			n2.putCharacter(ch)
+			ch = targetCh
 		}
-		n2.stopCheck()
-		resultLocalPart = n2.resultLocalPart()
-	} else if len(n.localPartNormalizer.localPart) == len(buf) {
-		// CAUTION: rework if having content rewrite in logic above.
-		// (make `buf` and `n.localPartNormalizer.localPart` have same size but different content)
-		resultLocalPart = n.localPartNormalizer.resultLocalPart()
-	} else {
-		n2 := normalizeLocalPartInstance{
-			localPart: make([]rune, 0, len(buf)),
-		}
-		for _, ch := range buf {
-			n2.putCharacter(ch)
+		if removeDots && (ch == '.') {
+			continue
 		}
-		n2.stopCheck()
-		resultLocalPart = n2.resultLocalPart()
+		mappedBuf = append(mappedBuf, ch)
+	}
+	if len(mappedBuf) == 0 {
+		return
+	}
+	resultLocalPart = requoteLocalPart(mappedBuf, false)
+	return
+}
+
+// resultCheckedLocalPart return checked local part, composed with NFC if
+// UnicodeNormalization option is set.
+func (n *normalizeInstance) resultCheckedLocalPart(opt *NormalizeOption) (localPart string) {
+	localPart = n.localPartNormalizer.resultLocalPart()
+	if opt.UnicodeNormalization {
+		localPart = composeText(localPart)
 	}
 	return
 }
@@ -414,27 +851,24 @@ func (n *normalizeInstance) resultDomainPart() (domainPart string) {
 	return
 }
 
+// resultNormalizedDomainPart return domain part for normalized address.
+// CAUTION: **Must** invoke after `canonicalizeDomain()` method.
+func (n *normalizeInstance) resultNormalizedDomainPart() (domainPart string) {
+	domainPart = n.normalizedDomainPart
+	if n.checkedIsIPLiteralPositive {
+		domainPart = "[" + domainPart + "]"
+	}
+	return
+}
+
 // NormalizeEmailAddress normalize given email adderss and return checked and normalized
 // email addresses.
 func NormalizeEmailAddress(emailAddress string, opt *NormalizeOption) (checkedEmailAddress, normalizedEmailAddress string, err error) {
-	if opt == nil {
-		opt = defaultNormalizeOption
-	}
-	normalizeInst := newNormalizeInstance(emailAddress)
-	if err = normalizeInst.runNormalize(); nil != err {
-		return
-	}
-	if err = normalizeInst.check(opt); nil != err {
-		return
-	}
-	checkedLocalPart := normalizeInst.localPartNormalizer.resultLocalPart()
-	normalizedLocalPart := normalizeInst.normalizeLocalPart(opt)
-	if len(normalizedLocalPart) == 0 {
-		err = ErrEmptyLocalPartAfterNormalize
+	parsedAddress, err := ParseEmailAddress(emailAddress, opt)
+	if nil != err {
 		return
 	}
-	domainPart := normalizeInst.resultDomainPart()
-	checkedEmailAddress = checkedLocalPart + "@" + domainPart
-	normalizedEmailAddress = normalizedLocalPart + "@" + domainPart
+	checkedEmailAddress = parsedAddress.CheckedEmailAddress()
+	normalizedEmailAddress = parsedAddress.NormalizedEmailAddress()
 	return
 }
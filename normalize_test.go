@@ -1,6 +1,9 @@
 package emailaddressnormalize_test
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
@@ -33,6 +36,10 @@ func TestNormalizeEmailAddress_DefaultOpt(t *testing.T) {
 	doNormalizeEmailAddressTest(t, nil, "User+subAddr@Example.Net", "user+subaddr@example.net", "user@example.net", false)
 	doNormalizeEmailAddressTest(t, nil, "U.se.r+subAddr@Example.Net", "u.se.r+subaddr@example.net", "user@example.net", false)
 	doNormalizeEmailAddressTest(t, nil, "U.se.r_Name+subAddr@Example.Net", "u.se.r_name+subaddr@example.net", "user_name@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "User@(Comment)Example.Net(Comment)", "user@example.net", "user@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, nil, "", "", "", true); err != emailaddressnormalize.ErrGivenAddressTooShort {
+		t.Errorf("unexpect error content for empty address: %v", err)
+	}
 }
 
 func TestNormalizeEmailAddress_AllowQuotedLocalPart(t *testing.T) {
@@ -62,6 +69,537 @@ func TestNormalizeEmailAddress_IPLiteral(t *testing.T) {
 	}
 	doNormalizeEmailAddressTest(t, opt, "user@127.0.0.1", "user@[127.0.0.1]", "user@[127.0.0.1]", false)
 	doNormalizeEmailAddressTest(t, opt, "user@[127.0.0.1]", "user@[127.0.0.1]", "user@[127.0.0.1]", false)
-	doNormalizeEmailAddressTest(t, opt, "user@2001:db8::ff00:42:8329", "user@[2001:db8::ff00:42:8329]", "user@[2001:db8::ff00:42:8329]", false)
-	doNormalizeEmailAddressTest(t, opt, "user@[2001:db8::ff00:42:8329]", "user@[2001:db8::ff00:42:8329]", "user@[2001:db8::ff00:42:8329]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@2001:db8::ff00:42:8329", "user@[IPv6:2001:db8::ff00:42:8329]", "user@[IPv6:2001:db8::ff00:42:8329]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[2001:db8::ff00:42:8329]", "user@[IPv6:2001:db8::ff00:42:8329]", "user@[IPv6:2001:db8::ff00:42:8329]", false)
+}
+
+func TestNormalizeEmailAddress_CollectAllViolations(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		CollectAllViolations: true,
+	}
+	err := doNormalizeEmailAddressTest(t, opt, "\"User #1\"@127.0.0.1", "", "", true)
+	for _, expectErr := range []error{
+		emailaddressnormalize.ErrGivenAddressHasIPLiteral,
+		emailaddressnormalize.ErrGivenAddressNeedQuote,
+		emailaddressnormalize.ErrGivenAddressContainSpecialCharacter,
+	} {
+		if !errors.Is(err, expectErr) {
+			t.Errorf("expecting error %v in collected violations: %v", expectErr, err)
+		}
+	}
+	if errors.Is(err, emailaddressnormalize.ErrGivenAddressLocalPartContainI18NCharacter) {
+		t.Errorf("unexpect i18n error in collected violations: %v", err)
+	}
+	var violations *emailaddressnormalize.ErrMultipleViolations
+	if !errors.As(err, &violations) {
+		t.Fatalf("expecting ErrMultipleViolations: %#v", err)
+	}
+	if len(violations.Errors) != 3 {
+		t.Errorf("unexpect count of violations: %v", violations.Errors)
+	}
+	doNormalizeEmailAddressTest(t, opt, "User@Example.Net", "user@example.net", "user@example.net", false)
+}
+
+func TestNormalizeEmailAddress_ReportCharacterPosition(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart:    true,
+		ReportCharacterPosition: true,
+	}
+	err := doNormalizeEmailAddressTest(t, opt, "\"使用者#1\"@Example.Net", "", "", true)
+	if !errors.Is(err, emailaddressnormalize.ErrGivenAddressContainSpecialCharacter) {
+		t.Errorf("unexpect error: %v", err)
+	}
+	var offendingChar *emailaddressnormalize.ErrOffendingCharacter
+	if !errors.As(err, &offendingChar) {
+		t.Fatalf("expecting ErrOffendingCharacter: %#v", err)
+	}
+	if (offendingChar.RuneOffset != 4) || (offendingChar.ByteOffset != 10) || (offendingChar.Character != '#') {
+		t.Errorf("unexpect offending character: %#v", offendingChar)
+	}
+	expectDiagnostic := "\"使用者#1\"@Example.Net\n       ^ given email address have special character"
+	if diagnostic := emailaddressnormalize.FormatDiagnostic("\"使用者#1\"@Example.Net", err); diagnostic != expectDiagnostic {
+		t.Errorf("unexpect diagnostic: %q, expect: %q", diagnostic, expectDiagnostic)
+	}
+	opt.CollectAllViolations = true
+	opt.AllowQuotedLocalPart = false
+	err = doNormalizeEmailAddressTest(t, opt, "Us.er..#1.@Example.Net", "", "", true)
+	expectDiagnostic = "Us.er..#1.@Example.Net\n      ^ given email address have to be quoted\n       ^ given email address have special character"
+	if diagnostic := emailaddressnormalize.FormatDiagnostic("Us.er..#1.@Example.Net", err); diagnostic != expectDiagnostic {
+		t.Errorf("unexpect diagnostic: %q, expect: %q", diagnostic, expectDiagnostic)
+	}
+}
+
+func TestNormalizeEmailAddress_ErrorCode(t *testing.T) {
+	err := doNormalizeEmailAddressTest(t, nil, "user@ab:cd.ef", "", "", true)
+	var combinationErr *emailaddressnormalize.ErrUnknownDomainCharacterCombination
+	if !errors.As(err, &combinationErr) {
+		t.Fatalf("expecting ErrUnknownDomainCharacterCombination: %#v", err)
+	}
+	if c := combinationErr.Class; (!c.HasHex()) || (!c.HasDot()) || (!c.HasColon()) || c.HasDecimal() || c.IsIDNA() || c.HasOtherCharacters() {
+		t.Errorf("unexpect domain character class: %v", c)
+	}
+	if combinationErr.Domain != "ab:cd.ef" {
+		t.Errorf("unexpect rejected domain: %s", combinationErr.Domain)
+	}
+	if code := emailaddressnormalize.ErrorCode(err); code != emailaddressnormalize.ErrorCodeUnknownDomainCharacterCombination {
+		t.Errorf("unexpect error code: %s", code)
+	}
+	opt := &emailaddressnormalize.NormalizeOption{
+		CollectAllViolations:    true,
+		ReportCharacterPosition: true,
+	}
+	err = doNormalizeEmailAddressTest(t, opt, "\"User #1\"@Example.Net", "", "", true)
+	expectCodes := []string{
+		emailaddressnormalize.ErrorCodeGivenAddressNeedQuote,
+		emailaddressnormalize.ErrorCodeGivenAddressContainSpecialCharacter,
+	}
+	if codes := emailaddressnormalize.ErrorCodes(err); !reflect.DeepEqual(codes, expectCodes) {
+		t.Errorf("unexpect error codes: %v, expect: %v", codes, expectCodes)
+	}
+}
+
+func TestNormalizeEmailAddress_DomainForm(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		DomainForm: emailaddressnormalize.DomainFormALabel,
+	}
+	doNormalizeEmailAddressTest(t, opt, "User@Example.Net", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "user@例子.台灣", "user@xn--fsqu00a.xn--kpry57d", "user@xn--fsqu00a.xn--kpry57d", false)
+	doNormalizeEmailAddressTest(t, opt, "user@XN--fsqu00a.xn--KPRY57D", "user@xn--fsqu00a.xn--kpry57d", "user@xn--fsqu00a.xn--kpry57d", false)
+	doNormalizeEmailAddressTest(t, opt, "user@ドメイン名例。ＪＰ", "user@xn--eckwd4c7cu47r2wf.jp", "user@xn--eckwd4c7cu47r2wf.jp", false)
+	err := doNormalizeEmailAddressTest(t, opt, "user@xn--a.com", "", "", true)
+	var idnaErr *emailaddressnormalize.ErrInvalidIDNADomain
+	if !errors.As(err, &idnaErr) {
+		t.Errorf("expecting ErrInvalidIDNADomain: %#v", err)
+	}
+	opt.DomainForm = emailaddressnormalize.DomainFormULabel
+	doNormalizeEmailAddressTest(t, opt, "user@xn--fsqu00a.xn--kpry57d", "user@例子.台灣", "user@例子.台灣", false)
+	doNormalizeEmailAddressTest(t, opt, "user@例子.台灣", "user@例子.台灣", "user@例子.台灣", false)
+}
+
+func TestNormalizeEmailAddress_DomainSyntax(t *testing.T) {
+	longLabel := strings.Repeat("a", 63)
+	doNormalizeEmailAddressTest(t, nil, "user@"+longLabel+".example.net", "user@"+longLabel+".example.net", "user@"+longLabel+".example.net", false)
+	if err := doNormalizeEmailAddressTest(t, nil, "user@example.net.", "", "", true); err != emailaddressnormalize.ErrDomainLabelEmpty {
+		t.Errorf("unexpect error content for trailing dot: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "user@a"+longLabel+".example.net", "", "", true); err != emailaddressnormalize.ErrDomainLabelTooLong {
+		t.Errorf("unexpect error content for too long label: %v", err)
+	}
+	longDomain := strings.Repeat(longLabel+".", 4) + "net"
+	if err := doNormalizeEmailAddressTest(t, nil, "user@"+longDomain, "", "", true); err != emailaddressnormalize.ErrDomainTooLong {
+		t.Errorf("unexpect error content for too long domain: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "user@"+"台灣的例子網域名稱測試用很長的標籤名字與更多文字"+".example.net", "", "", true); err != emailaddressnormalize.ErrDomainLabelTooLong {
+		t.Errorf("unexpect error content for too long label in A-label form: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "user@-example.net", "", "", true); err != emailaddressnormalize.ErrDomainLabelHyphenAtBoundary {
+		t.Errorf("unexpect error content for leading hyphen: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "user@example-.net", "", "", true); err != emailaddressnormalize.ErrDomainLabelHyphenAtBoundary {
+		t.Errorf("unexpect error content for trailing hyphen: %v", err)
+	}
+	for _, addr := range []string{"a@b@c.com", "user@exa_mple.com", "user@exa!mple.com", "user@exa mple.com", "user@1.2.3.4!"} {
+		if err := doNormalizeEmailAddressTest(t, nil, addr, "", "", true); err != emailaddressnormalize.ErrDomainInvalidCharacter {
+			t.Errorf("unexpect error content for invalid domain character (%s): %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"user@..example..com", "user@example..com", "user@.example.com"} {
+		if err := doNormalizeEmailAddressTest(t, nil, addr, "", "", true); err != emailaddressnormalize.ErrDomainLabelEmpty {
+			t.Errorf("unexpect error content for empty label (%s): %v", addr, err)
+		}
+	}
+	doNormalizeEmailAddressTest(t, nil, "user@ example.net ", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "user@example.123", "user@example.123", "user@example.123", false)
+	opt := &emailaddressnormalize.NormalizeOption{
+		ReportCharacterPosition: true,
+	}
+	err := doNormalizeEmailAddressTest(t, opt, "user@exa_mple.com", "", "", true)
+	if e, ok := err.(*emailaddressnormalize.ErrOffendingCharacter); !ok || (e.Err != emailaddressnormalize.ErrDomainInvalidCharacter) || (e.RuneOffset != 8) || (e.Character != '_') {
+		t.Errorf("unexpect error content for position of invalid domain character: %v", err)
+	}
+	opt = &emailaddressnormalize.NormalizeOption{
+		RejectNumericTLD: true,
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@example.123", "", "", true); err != emailaddressnormalize.ErrDomainNumericTLD {
+		t.Errorf("unexpect error content for numeric TLD: %v", err)
+	}
+}
+
+func TestNormalizeEmailAddress_LengthLimit(t *testing.T) {
+	localPart := strings.Repeat("u", 64)
+	doNormalizeEmailAddressTest(t, nil, localPart+"@example.net", localPart+"@example.net", localPart+"@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, nil, "u"+localPart+"@example.net", "", "", true); err != emailaddressnormalize.ErrLocalPartTooLong {
+		t.Errorf("unexpect error content for too long local part: %v", err)
+	}
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowLocalPartInternationalChars: true,
+	}
+	i18nLocalPart := strings.Repeat("使", 21)
+	doNormalizeEmailAddressTest(t, opt, i18nLocalPart+"@example.net", i18nLocalPart+"@example.net", i18nLocalPart+"@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, opt, i18nLocalPart+"uu@example.net", "", "", true); err != emailaddressnormalize.ErrLocalPartTooLong {
+		t.Errorf("unexpect error content for too long i18n local part: %v", err)
+	}
+	domainPart := strings.Repeat(strings.Repeat("d", 60)+".", 4) + "net"
+	if err := doNormalizeEmailAddressTest(t, nil, localPart+"@"+domainPart, "", "", true); err != emailaddressnormalize.ErrPathTooLong {
+		t.Errorf("unexpect error content for too long path: %v", err)
+	}
+	opt = &emailaddressnormalize.NormalizeOption{
+		LocalPartLengthLimit: -1,
+		PathLengthLimit:      512,
+	}
+	doNormalizeEmailAddressTest(t, opt, "u"+localPart+"@"+domainPart, "u"+localPart+"@"+domainPart, "u"+localPart+"@"+domainPart, false)
+	opt.LocalPartLengthLimit = 8
+	if err := doNormalizeEmailAddressTest(t, opt, "username1@example.net", "", "", true); err != emailaddressnormalize.ErrLocalPartTooLong {
+		t.Errorf("unexpect error content for local part longer than custom limit: %v", err)
+	}
+}
+
+func TestNormalizeEmailAddress_RemoveLocalPartDotsWith(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		RemoveLocalPartDots: true,
+		RemoveLocalPartDotsWith: func(domainPart string) (removeDots bool) {
+			return domainPart == "gmail.com"
+		},
+	}
+	doNormalizeEmailAddressTest(t, opt, "First.Last@GMail.com", "first.last@gmail.com", "firstlast@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last@Example.com", "first.last@example.com", "first.last@example.com", false)
+}
+
+func TestNormalizeEmailAddress_SubAddressingSeparators(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart: true,
+		RemoveSubAddressingWith: func(domainPart string) (subAddressChars []rune) {
+			return ([]rune)("=_-")
+		},
+	}
+	doNormalizeEmailAddressTest(t, opt, "user=tag@example.net", "user=tag@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "user_a-tag@example.net", "user_a-tag@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"user=a\"_tag@example.net", "user=a_tag@example.net", "user=a@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"user=a\\\\b\"@example.net", "\"user=a\\\\b\"@example.net", "\"user=a\\\\b\"@example.net", false)
+	opt.SubAddressingOccurrence = emailaddressnormalize.SubAddressingLastOccurrence
+	doNormalizeEmailAddressTest(t, opt, "user_a-tag@example.net", "user_a-tag@example.net", "user_a@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"user-a\"-b-c@example.net", "user-a-b-c@example.net", "user-a-b@example.net", false)
+}
+
+func TestNormalizeEmailAddress_MapLocalPartCharactersWith(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart: true,
+		MapLocalPartCharactersWith: func(domainPart string) (mapping map[rune]rune) {
+			if domainPart != "example.net" {
+				return nil
+			}
+			return map[rune]rune{
+				'_': '.',
+				'-': emailaddressnormalize.DeleteCharacter,
+			}
+		},
+	}
+	doNormalizeEmailAddressTest(t, opt, "First_Last-X@Example.net", "first_last-x@example.net", "first.lastx@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "First_Last-X@Example.com", "first_last-x@example.com", "first_last-x@example.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First__Last@Example.net", "first__last@example.net", "\"first..last\"@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, opt, "---@Example.net", "", "", true); err != emailaddressnormalize.ErrEmptyLocalPartAfterNormalize {
+		t.Errorf("unexpect error content for local part removed by mapping: %v", err)
+	}
+}
+
+func TestNormalizeEmailAddress_PreserveLocalPartCase(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart:  true,
+		PreserveLocalPartCase: true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "John.Smith@Example.Net", "John.Smith@example.net", "john.smith@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"John Smith\"@Example.Net", "\"John Smith\"@example.net", "\"john smith\"@example.net", false)
+	opt.RemoveSubAddressingWith = func(domainPart string) (subAddressChars []rune) {
+		return ([]rune)("+")
+	}
+	opt.RemoveLocalPartDots = true
+	doNormalizeEmailAddressTest(t, opt, "John.Smith+Tag@Example.Net", "John.Smith+Tag@example.net", "johnsmith@example.net", false)
+}
+
+func TestNormalizeEmailAddress_UnicodeNormalization(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowLocalPartInternationalChars: true,
+		UnicodeNormalization:             true,
+		KeepFullWidthCharacters:          true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "Stra\u00dfe@Example.Net", "stra\u00dfe@example.net", "strasse@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "STRASSE@Example.Net", "strasse@example.net", "strasse@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "Cafe\u0301@Example.Net", "caf\u00e9@example.net", "caf\u00e9@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "Caf\u00e9@Example.Net", "caf\u00e9@example.net", "caf\u00e9@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "user@\uff25\uff58\uff41\uff4d\uff50\uff4c\uff45.Net", "user@\uff45\uff58\uff41\uff4d\uff50\uff4c\uff45.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "user@Exa\u0301mple.Net", "user@ex\u00e1mple.net", "user@ex\u00e1mple.net", false)
+	composedLocalPart := strings.Repeat("\u00e9", 32)
+	doNormalizeEmailAddressTest(t, opt, strings.Repeat("e\u0301", 32)+"@Example.Net", composedLocalPart+"@example.net", composedLocalPart+"@example.net", false)
+}
+
+func TestNormalizeEmailAddress_FoldFullWidthCharacters(t *testing.T) {
+	doNormalizeEmailAddressTest(t, nil, "Ｕｓｅｒ＋ｔａｇ＠Ｅｘａｍｐｌｅ．Ｎｅｔ", "user+tag@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "User＠Example。Net", "user@example.net", "user@example.net", false)
+	opt := &emailaddressnormalize.NormalizeOption{}
+	emailaddressnormalize.DefaultProviderRules().ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "User＋Tag＠Gmail．com", "user+tag@gmail.com", "user@gmail.com", false)
+	opt = &emailaddressnormalize.NormalizeOption{
+		AllowLocalPartInternationalChars: true,
+		AllowIPLiteral:                   true,
+		KeepFullWidthCharacters:          true,
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "User＠Example.Net", "", "", true); err != emailaddressnormalize.ErrEmptyDomainAfterCheck {
+		t.Errorf("unexpect error content for full-width at sign without folding: %v", err)
+	}
+}
+
+func TestNormalizeEmailAddress_InvisibleCharacters(t *testing.T) {
+	doNormalizeEmailAddressTest(t, nil, "User\r\n@Example.Net", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "Us\u200der@Example.Net", "user@example.net", "user@example.net", false)
+	opt := &emailaddressnormalize.NormalizeOption{
+		RejectControlCharacters: true,
+		RejectFormatCharacters:  true,
+		RejectBidiControls:      true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "User@Example.Net", "user@example.net", "user@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, opt, "User\r\n@Example.Net", "", "", true); err != emailaddressnormalize.ErrGivenAddressContainControlCharacter {
+		t.Errorf("unexpect error for control character: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@Exam\u200dple.Net", "", "", true); err != emailaddressnormalize.ErrGivenAddressContainFormatCharacter {
+		t.Errorf("unexpect error for format character: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "\u202eUser@Example.Net", "", "", true); err != emailaddressnormalize.ErrGivenAddressContainBidiControl {
+		t.Errorf("unexpect error for bidi control: %v", err)
+	}
+	opt.ReportCharacterPosition = true
+	err := doNormalizeEmailAddressTest(t, opt, "使用者\u2067@Example.Net", "", "", true)
+	var offendingChar *emailaddressnormalize.ErrOffendingCharacter
+	if !errors.As(err, &offendingChar) {
+		t.Fatalf("expecting ErrOffendingCharacter: %#v", err)
+	}
+	if (offendingChar.Err != emailaddressnormalize.ErrGivenAddressContainBidiControl) || (offendingChar.RuneOffset != 3) || (offendingChar.ByteOffset != 9) {
+		t.Errorf("unexpect offending character: %#v", offendingChar)
+	}
+}
+
+func TestNormalizeEmailAddress_Comments(t *testing.T) {
+	doNormalizeEmailAddressTest(t, nil, "User(a(b)c)@Example.Net", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "User(a\\)b)@Example.Net", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "User(a\"b)@Example.Net", "user@example.net", "user@example.net", false)
+	doNormalizeEmailAddressTest(t, nil, "User@Example.Net(a(b)c)", "user@example.net", "user@example.net", false)
+	if err := doNormalizeEmailAddressTest(t, nil, "User(a(b)c@Example.Net", "", "", true); err != emailaddressnormalize.ErrUnbalancedParenthesis {
+		t.Errorf("unexpect error for unclosed comment: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "Us)er@Example.Net", "", "", true); err != emailaddressnormalize.ErrUnbalancedParenthesis {
+		t.Errorf("unexpect error for stray close parenthesis: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, nil, "User@Example.Net(a", "", "", true); err != emailaddressnormalize.ErrUnbalancedParenthesis {
+		t.Errorf("unexpect error for unclosed domain comment: %v", err)
+	}
+	opt := &emailaddressnormalize.NormalizeOption{
+		ReportCharacterPosition: true,
+	}
+	err := doNormalizeEmailAddressTest(t, opt, "User@Example.Net)", "", "", true)
+	var offendingChar *emailaddressnormalize.ErrOffendingCharacter
+	if !errors.As(err, &offendingChar) {
+		t.Fatalf("expecting ErrOffendingCharacter: %#v", err)
+	}
+	if (offendingChar.Err != emailaddressnormalize.ErrUnbalancedParenthesis) || (offendingChar.RuneOffset != 16) || (offendingChar.Character != ')') {
+		t.Errorf("unexpect offending character: %#v", offendingChar)
+	}
+	opt = &emailaddressnormalize.NormalizeOption{
+		CollectAllViolations: true,
+	}
+	err = doNormalizeEmailAddressTest(t, opt, "Us)er@Example.Net)", "", "", true)
+	var violations *emailaddressnormalize.ErrMultipleViolations
+	if !errors.As(err, &violations) {
+		t.Fatalf("expecting ErrMultipleViolations: %#v", err)
+	}
+	if (len(violations.Errors) != 2) || (violations.Errors[0] != emailaddressnormalize.ErrUnbalancedParenthesis) || (violations.Errors[1] != emailaddressnormalize.ErrUnbalancedParenthesis) {
+		t.Errorf("unexpect violations for unbalanced parenthesis in both parts: %v", violations.Errors)
+	}
+}
+
+func TestNormalizeEmailAddress_AddressLiteral(t *testing.T) {
+	if err := doNormalizeEmailAddressTest(t, nil, "user@[IPv6:2001:db8::1]", "", "", true); err != emailaddressnormalize.ErrGivenAddressHasIPLiteral {
+		t.Errorf("unexpect error content for tagged IPv6 literal: %v", err)
+	}
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowIPLiteral: true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[ipv6:2001:DB8:0:0:0:0:0:1]", "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[2001:DB8:0::1]", "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[x-tag:Some.Content]", "user@[x-tag:Some.Content]", "user@[x-tag:Some.Content]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[192.0.2.1](comment)", "user@[192.0.2.1]", "user@[192.0.2.1]", false)
+	for _, addr := range []string{
+		"user@[]",
+		"user@[192.0.2]",
+		"user@[192.0.2.256]",
+		"user@[IPv6:192.0.2.1]",
+		"user@[IPv6:2001:db8::g]",
+		"user@[IPv6:::ffff:192.0.2.1]",
+		"user@[x-tag:]",
+		"user@[-tag:content]",
+		"user@[x-tag:a[b]",
+		"user@[192.0.2.1",
+		"user@[192.0.2.1]x",
+		"user@x[192.0.2.1]",
+	} {
+		if err := doNormalizeEmailAddressTest(t, opt, addr, "", "", true); err != emailaddressnormalize.ErrInvalidAddressLiteral {
+			t.Errorf("unexpect error content for %s: %v", addr, err)
+		}
+	}
+}
+
+func TestNormalizeEmailAddress_IPAddressClass(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowIPLiteral: true,
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@[IPv6:::ffff:192.0.2.1]", "", "", true); err != emailaddressnormalize.ErrInvalidAddressLiteral {
+		t.Errorf("unexpect error content for IPv4-mapped address literal: %v", err)
+	}
+	opt.AllowIPv4MappedAddressLiteral = true
+	doNormalizeEmailAddressTest(t, opt, "user@[IPv6:::FFFF:192.0.2.1]", "user@[IPv6:::ffff:192.0.2.1]", "user@[IPv6:::ffff:192.0.2.1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[IPv6:::ffff:c000:201]", "user@[IPv6:::ffff:192.0.2.1]", "user@[IPv6:::ffff:192.0.2.1]", false)
+	opt.RejectIPAddressClasses = emailaddressnormalize.IPAddressLoopback | emailaddressnormalize.IPAddressPrivate |
+		emailaddressnormalize.IPAddressLinkLocal | emailaddressnormalize.IPAddressMulticast | emailaddressnormalize.IPAddressUnspecified
+	doNormalizeEmailAddressTest(t, opt, "user@[192.0.2.1]", "user@[192.0.2.1]", "user@[192.0.2.1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[x-tag:content]", "user@[x-tag:content]", "user@[x-tag:content]", false)
+	for _, c := range []struct {
+		addr  string
+		class emailaddressnormalize.IPAddressClass
+	}{
+		{"user@[127.0.0.1]", emailaddressnormalize.IPAddressLoopback},
+		{"user@127.0.0.1", emailaddressnormalize.IPAddressLoopback},
+		{"user@[IPv6:::1]", emailaddressnormalize.IPAddressLoopback},
+		{"user@[IPv6:::ffff:127.0.0.1]", emailaddressnormalize.IPAddressLoopback},
+		{"user@[IPv6:::127.0.0.1]", emailaddressnormalize.IPAddressLoopback},
+		{"user@[IPv6:::10.1.2.3]", emailaddressnormalize.IPAddressPrivate},
+		{"user@[10.1.2.3]", emailaddressnormalize.IPAddressPrivate},
+		{"user@[172.31.0.1]", emailaddressnormalize.IPAddressPrivate},
+		{"user@[192.168.1.1]", emailaddressnormalize.IPAddressPrivate},
+		{"user@[IPv6:fd00::1]", emailaddressnormalize.IPAddressPrivate},
+		{"user@[169.254.1.1]", emailaddressnormalize.IPAddressLinkLocal},
+		{"user@[IPv6:fe80::1]", emailaddressnormalize.IPAddressLinkLocal},
+		{"user@[224.0.1.1]", emailaddressnormalize.IPAddressMulticast},
+		{"user@[IPv6:ff02::1]", emailaddressnormalize.IPAddressLinkLocal | emailaddressnormalize.IPAddressMulticast},
+		{"user@[0.0.0.0]", emailaddressnormalize.IPAddressUnspecified},
+		{"user@[IPv6:::]", emailaddressnormalize.IPAddressUnspecified},
+	} {
+		err := doNormalizeEmailAddressTest(t, opt, c.addr, "", "", true)
+		var classErr *emailaddressnormalize.ErrRejectedIPAddressClass
+		if !errors.As(err, &classErr) {
+			t.Errorf("expecting ErrRejectedIPAddressClass for %s: %#v", c.addr, err)
+		} else if classErr.Class != c.class {
+			t.Errorf("unexpect rejected class for %s: %v, expect %v", c.addr, classErr.Class, c.class)
+		}
+	}
+	opt.RejectIPAddressClasses = emailaddressnormalize.IPAddressDocumentation
+	err := doNormalizeEmailAddressTest(t, opt, "user@[IPv6:2001:db8::1]", "", "", true)
+	if code := emailaddressnormalize.ErrorCode(err); code != emailaddressnormalize.ErrorCodeRejectedIPAddressClass {
+		t.Errorf("unexpect error code: %s", code)
+	}
+}
+
+func TestNormalizeEmailAddress_BareIPDomain(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowIPLiteral: true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "user@192.0.2.1", "user@[192.0.2.1]", "user@[192.0.2.1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@2001:DB8:0:0::1", "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", false)
+	doNormalizeEmailAddressTest(t, opt, "user@cafe.de", "user@cafe.de", "user@cafe.de", false)
+	for _, addr := range []string{
+		"user@1.2.3",
+		"user@1.2.3.4.5",
+		"user@192.0.2.256",
+		"user@2001:db8::g",
+		"user@ab:cd.ef",
+	} {
+		err := doNormalizeEmailAddressTest(t, opt, addr, "", "", true)
+		var combinationErr *emailaddressnormalize.ErrUnknownDomainCharacterCombination
+		if !errors.As(err, &combinationErr) {
+			t.Errorf("expecting ErrUnknownDomainCharacterCombination for %s: %#v", addr, err)
+		}
+	}
+	opt.BareIPDomain = emailaddressnormalize.BareIPDomainReject
+	doNormalizeEmailAddressTest(t, opt, "user@[192.0.2.1]", "user@[192.0.2.1]", "user@[192.0.2.1]", false)
+	if err := doNormalizeEmailAddressTest(t, opt, "user@192.0.2.1", "", "", true); err != emailaddressnormalize.ErrBareIPDomain {
+		t.Errorf("unexpect error content for bare IPv4 domain: %v", err)
+	}
+	if err := doNormalizeEmailAddressTest(t, opt, "user@2001:db8::1", "", "", true); err != emailaddressnormalize.ErrBareIPDomain {
+		t.Errorf("unexpect error content for bare IPv6 domain: %v", err)
+	}
+	opt.BareIPDomain = emailaddressnormalize.BareIPDomainAsDNSName
+	doNormalizeEmailAddressTest(t, opt, "user@1.2.3", "user@1.2.3", "user@1.2.3", false)
+	doNormalizeEmailAddressTest(t, opt, "user@192.0.2.1", "user@192.0.2.1", "user@192.0.2.1", false)
+	opt.RejectNumericTLD = true
+	if err := doNormalizeEmailAddressTest(t, opt, "user@192.0.2.1", "", "", true); err != emailaddressnormalize.ErrDomainNumericTLD {
+		t.Errorf("unexpect error content for numeric domain: %v", err)
+	}
+	err := doNormalizeEmailAddressTest(t, opt, "user@2001:db8::1", "", "", true)
+	var combinationErr *emailaddressnormalize.ErrUnknownDomainCharacterCombination
+	if !errors.As(err, &combinationErr) {
+		t.Errorf("expecting ErrUnknownDomainCharacterCombination: %#v", err)
+	}
+}
+
+func TestNormalizeEmailAddress_StrictRFC(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart: true,
+		AllowIPLiteral:       true,
+		StrictRFC:            true,
+	}
+	doNormalizeEmailAddressTest(t, opt, "User.Name@Example.Net", "user.name@example.net", "user.name@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"User Name\"@Example.Net", "\"user name\"@example.net", "\"user name\"@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "\"User\\\"Name\"@Example.Net", "\"user\\\"name\"@example.net", "\"user\\\"name\"@example.net", false)
+	doNormalizeEmailAddressTest(t, opt, "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", "user@[IPv6:2001:db8::1]", false)
+	for _, c := range []struct {
+		addr string
+		rule string
+	}{
+		{".user@example.net", emailaddressnormalize.GrammarRuleDotString},
+		{"user.@example.net", emailaddressnormalize.GrammarRuleDotString},
+		{"us..er@example.net", emailaddressnormalize.GrammarRuleDotString},
+		{"us er@example.net", emailaddressnormalize.GrammarRuleAtom},
+		{"us(comment)er@example.net", emailaddressnormalize.GrammarRuleAtom},
+		{"us\\@er@example.net", emailaddressnormalize.GrammarRuleAtom},
+		{"@example.net", emailaddressnormalize.GrammarRuleLocalPart},
+		{"\"user@example.net", emailaddressnormalize.GrammarRuleQuotedString},
+		{"\"us\ter\"@example.net", emailaddressnormalize.GrammarRuleQcontentSMTP},
+		{"\"us\\\ter\"@example.net", emailaddressnormalize.GrammarRuleQuotedPairSMTP},
+		{"\"user\"x@example.net", emailaddressnormalize.GrammarRuleMailbox},
+		{"\"us\".\"er\"@example.net", emailaddressnormalize.GrammarRuleMailbox},
+		{"user.example.net", emailaddressnormalize.GrammarRuleMailbox},
+		{"user@example.net (comment)", emailaddressnormalize.GrammarRuleSubDomain},
+		{"user@", emailaddressnormalize.GrammarRuleDomain},
+		{"user@example.", emailaddressnormalize.GrammarRuleDomain},
+		{"user@example..net", emailaddressnormalize.GrammarRuleSubDomain},
+		{"user@-example.net", emailaddressnormalize.GrammarRuleSubDomain},
+		{"user@example-.net", emailaddressnormalize.GrammarRuleLdhStr},
+		{"user@example.net-", emailaddressnormalize.GrammarRuleLdhStr},
+		{"user@[192.0.2.256]", emailaddressnormalize.GrammarRuleAddressLiteral},
+		{"user@[192.0.2.1", emailaddressnormalize.GrammarRuleAddressLiteral},
+		{"user@[2001:db8::1]", emailaddressnormalize.GrammarRuleAddressLiteral},
+		{"user@[::1]", emailaddressnormalize.GrammarRuleAddressLiteral},
+		{"user@[192.0.2.1]x", emailaddressnormalize.GrammarRuleMailbox},
+	} {
+		err := doNormalizeEmailAddressTest(t, opt, c.addr, "", "", true)
+		var grammarErr *emailaddressnormalize.GrammarError
+		if !errors.As(err, &grammarErr) {
+			t.Errorf("expecting GrammarError for %s: %#v", c.addr, err)
+		} else if grammarErr.Rule != c.rule {
+			t.Errorf("unexpect violated rule for %s: %s, expect %s", c.addr, grammarErr.Rule, c.rule)
+		}
+		if !errors.Is(err, emailaddressnormalize.ErrStrictRFCViolation) {
+			t.Errorf("expecting ErrStrictRFCViolation for %s: %v", c.addr, err)
+		}
+	}
+	opt.ReportCharacterPosition = true
+	err := doNormalizeEmailAddressTest(t, opt, "us..er@example.net", "", "", true)
+	if code := emailaddressnormalize.ErrorCode(err); code != emailaddressnormalize.ErrorCodeStrictRFCViolation {
+		t.Errorf("unexpect error code: %s", code)
+	}
+	var offendingChar *emailaddressnormalize.ErrOffendingCharacter
+	if !errors.As(err, &offendingChar) {
+		t.Fatalf("expecting ErrOffendingCharacter: %#v", err)
+	}
+	if (offendingChar.RuneOffset != 3) || (offendingChar.Character != '.') {
+		t.Errorf("unexpect offending character: %#v", offendingChar)
+	}
 }
@@ -3,6 +3,30 @@ package emailaddressnormalize
 // SubAddressingCharactersFunc represent callable return sub-addressing characters of given domain part.
 type SubAddressingCharactersFunc func(domainPart string) (subAddressChars []rune)
 
+// LocalPartDotsRemovalFunc represent callable return if dots in local part should be removed for given domain part.
+type LocalPartDotsRemovalFunc func(domainPart string) (removeDots bool)
+
+// DomainCanonicalizeFunc represent callable return canonical domain of given domain part.
+type DomainCanonicalizeFunc func(domainPart string) (canonicalDomain string)
+
+// SubAddressingOccurrence select which occurrence of sub-addressing characters starts the sub-address.
+type SubAddressingOccurrence int
+
+// Occurrences of sub-addressing characters.
+const (
+	SubAddressingFirstOccurrence SubAddressingOccurrence = iota
+	SubAddressingLastOccurrence
+)
+
+// LocalPartCharacterMappingFunc represent callable return character equivalences of
+// local part for given domain part. Characters mapped to DeleteCharacter are removed.
+type LocalPartCharacterMappingFunc func(domainPart string) (mapping map[rune]rune)
+
+// SubdomainAddressingFunc represent callable return base domain if given domain part
+// is in form of `mailbox.base-domain` for a provider supports subdomain addressing.
+// Empty string should be returned if subdomain addressing does not apply.
+type SubdomainAddressingFunc func(domainPart string) (baseDomain string)
+
 // NormalizeOption contain parameters for normalize function.
 type NormalizeOption struct {
 	AllowQuotedLocalPart             bool
@@ -10,8 +34,114 @@ type NormalizeOption struct {
 	AllowLocalPartInternationalChars bool
 	AllowIPLiteral                   bool
 
+	// AllowIPv4MappedAddressLiteral accept IPv4-mapped IPv6 address
+	// (eg: [IPv6:::ffff:192.0.2.1]) as address literal.
+	AllowIPv4MappedAddressLiteral bool
+
+	// RejectIPAddressClasses reject IP address literals belong to any of
+	// given classes (eg: IPAddressLoopback | IPAddressPrivate).
+	// Zero value allow all classes.
+	RejectIPAddressClasses IPAddressClass
+
+	// BareIPDomain select how IP address given as domain part without
+	// brackets (eg: `user@192.0.2.1`) is handled.
+	BareIPDomain BareIPDomainPolicy
+
+	// PreserveLocalPartCase keep the case of local part in checked address.
+	// Local part of normalized address is still case-folded.
+	PreserveLocalPartCase bool
+
+	// UnicodeNormalization apply Unicode normalization to local part and
+	// domain part as RFC 6530 suggested: NFC for checked address, NFKC and
+	// full case folding for normalized address.
+	UnicodeNormalization bool
+
+	// KeepFullWidthCharacters disable mapping full-width ASCII variants (eg:
+	// `＠`, `＋`, full-width letters and digits) in given address into ASCII
+	// characters. Full-width characters are folded when not set.
+	KeepFullWidthCharacters bool
+
+	// MixedScriptPolicy select how local part and domain labels mixing
+	// scripts (eg: Cyrillic and Latin) are handled.
+	MixedScriptPolicy MixedScriptPolicy
+
+	// RejectControlCharacters reject address contain control characters
+	// (eg: CR and LF) instead of silently dropping them.
+	RejectControlCharacters bool
+
+	// RejectFormatCharacters reject address contain format characters
+	// (Unicode category Cf, eg: zero-width joiner) other than bidi controls.
+	RejectFormatCharacters bool
+
+	// RejectBidiControls reject address contain bidirectional control characters.
+	RejectBidiControls bool
+
+	// StrictRFC reject given address does not match RFC 5321 Mailbox grammar
+	// (eg: leading, trailing or consecutive dots in unquoted local part,
+	// unescaped specials, comments and other obsolete syntax) with a
+	// GrammarError, instead of repairing it.
+	StrictRFC bool
+
+	// CollectAllViolations make check process run all checks and report
+	// found violations with an ErrMultipleViolations.
+	CollectAllViolations bool
+
+	// ReportCharacterPosition make violations caused by a character reported
+	// with an ErrOffendingCharacter which wraps the sentinel error.
+	ReportCharacterPosition bool
+
+	// DomainForm select the form of domain part in results. Domain part is
+	// validated with UTS #46 and IDNA2008 rules when form other than
+	// DomainFormAsGiven is selected.
+	DomainForm DomainForm
+
+	// RejectNumericTLD reject domain part with all-numeric top-level domain.
+	RejectNumericTLD bool
+
+	// LocalPartLengthLimit is the maximum length of checked local part in
+	// UTF-8 octets. Default (64) is used when set to zero and check is
+	// disabled when set to negative value.
+	LocalPartLengthLimit int
+
+	// PathLengthLimit is the maximum length of forward-path (checked address
+	// with angle brackets) in UTF-8 octets. Default (256) is used when set to
+	// zero and check is disabled when set to negative value.
+	PathLengthLimit int
+
 	RemoveSubAddressingWith SubAddressingCharactersFunc
 	RemoveLocalPartDots     bool
+
+	// SubAddressingOccurrence select if the first or the last unquoted
+	// sub-addressing character starts the sub-address.
+	SubAddressingOccurrence SubAddressingOccurrence
+
+	// RemoveLocalPartDotsWith decide if dots in local part should be removed
+	// per domain part. RemoveLocalPartDots is ignored when this is set.
+	RemoveLocalPartDotsWith LocalPartDotsRemovalFunc
+
+	// CanonicalizeDomainWith rewrite domain part of normalized address into
+	// canonical form. The rewritten domain part is used to look up the
+	// per-domain rules of local part.
+	CanonicalizeDomainWith DomainCanonicalizeFunc
+
+	// SubdomainAddressingWith recognize subdomain addressing (eg:
+	// `tag@mailbox.fastmail.com`). Normalized address of such address is
+	// rewritten into base mailbox (eg: `mailbox@fastmail.com`) and the local
+	// part is reported as sub-address.
+	SubdomainAddressingWith SubdomainAddressingFunc
+
+	// MapLocalPartCharactersWith fold equivalent characters in local part of
+	// normalized address into canonical one. The mapping is applied after
+	// sub-address removal and before dot removal.
+	MapLocalPartCharactersWith LocalPartCharacterMappingFunc
+}
+
+// shouldRemoveLocalPartDots check if dots in local part should be removed for given domain part.
+func (opt *NormalizeOption) shouldRemoveLocalPartDots(domainPart string) bool {
+	if opt.RemoveLocalPartDotsWith != nil {
+		return opt.RemoveLocalPartDotsWith(domainPart)
+	}
+	return opt.RemoveLocalPartDots
 }
 
 var defaultSubAddressChars = ([]rune)("+%")
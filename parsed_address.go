@@ -0,0 +1,175 @@
+package emailaddressnormalize
+
+// ParsedAddress contain parts and properties of a checked and normalized email address.
+type ParsedAddress struct {
+	// RawLocalPart is the local part as it appears in given address.
+	RawLocalPart string
+
+	// CheckedLocalPart is the local part with minimal fixes.
+	CheckedLocalPart string
+
+	// NormalizedLocalPart is the checked local part with normalizations applied.
+	NormalizedLocalPart string
+
+	// Domain is the checked domain part.
+	Domain string
+
+	// NormalizedDomain is the domain part of normalized address.
+	NormalizedDomain string
+
+	// DomainALabel is the A-label (xn--) form of domain part.
+	// Only available when DomainForm option is not DomainFormAsGiven.
+	DomainALabel string
+
+	// DomainULabel is the U-label (Unicode) form of domain part.
+	// Only available when DomainForm option is not DomainFormAsGiven.
+	DomainULabel string
+
+	// RemovedSubAddress is the sub-address (including the separator) removed
+	// from local part on normalization.
+	RemovedSubAddress string
+
+	// SubAddressSeparator is the sub-addressing character which starts the
+	// removed sub-address. Zero if no sub-address is removed.
+	SubAddressSeparator rune
+
+	// SubAddressTag is the removed sub-address without the separator.
+	SubAddressTag string
+
+	// SubAddressInDomain indicate the address use subdomain addressing and
+	// SubAddressTag is the local part of given address.
+	SubAddressInDomain bool
+
+	// SubAddressOffset is the offset in runes of the separator in given address.
+	// Only meaningful when SubAddressSeparator is not zero.
+	SubAddressOffset int
+
+	// Comments contain the comments dropped from given address, comments
+	// of local part come before comments of domain part.
+	Comments []string
+
+	// WasQuoted indicate the local part is quoted in given address.
+	WasQuoted bool
+
+	// NeedQuote indicate the checked local part is quoted.
+	NeedQuote bool
+
+	// IsIPLiteral indicate the domain part is an IP literal.
+	IsIPLiteral bool
+
+	// WasBracketed indicate the domain part is an address literal with
+	// brackets in given address. Brackets are added to IP address without
+	// brackets when IsIPLiteral is set but WasBracketed is not.
+	WasBracketed bool
+
+	// AddressLiteralTag is the tag of address literal in domain part
+	// (eg: AddressLiteralTagIPv6). Empty for IPv4 address literal.
+	AddressLiteralTag string
+
+	// IPAddressClass is the special-purpose ranges the IP address literal
+	// in domain part belongs to.
+	IPAddressClass IPAddressClass
+
+	// LocalPartHasI18NCharacter indicate local part contain international character.
+	LocalPartHasI18NCharacter bool
+
+	// DomainHasI18NCharacter indicate domain part contain international character.
+	DomainHasI18NCharacter bool
+
+	// HasControlCharacter indicate given address contain control characters.
+	HasControlCharacter bool
+
+	// HasFormatCharacter indicate given address contain format characters
+	// other than bidi controls.
+	HasFormatCharacter bool
+
+	// HasBidiControl indicate given address contain bidi control characters.
+	HasBidiControl bool
+
+	// LocalPartIsMixedScript indicate local part mix characters of different scripts.
+	// Only available when MixedScriptPolicy option is not MixedScriptIgnore.
+	LocalPartIsMixedScript bool
+
+	// DomainIsMixedScript indicate a label of domain part mix characters of different scripts.
+	// Only available when MixedScriptPolicy option is not MixedScriptIgnore.
+	DomainIsMixedScript bool
+}
+
+// Skeleton return the UTS #39 skeleton of normalized address.
+func (p *ParsedAddress) Skeleton() string {
+	return Skeleton(p.NormalizedEmailAddress())
+}
+
+// CheckedEmailAddress return email address with minimal fixes.
+func (p *ParsedAddress) CheckedEmailAddress() string {
+	return p.CheckedLocalPart + "@" + p.Domain
+}
+
+// NormalizedEmailAddress return checked email address with normalizations applied.
+func (p *ParsedAddress) NormalizedEmailAddress() string {
+	return p.NormalizedLocalPart + "@" + p.NormalizedDomain
+}
+
+// ParseEmailAddress check and normalize given email address and return the
+// parts and properties found.
+func ParseEmailAddress(emailAddress string, opt *NormalizeOption) (parsedAddress *ParsedAddress, err error) {
+	if opt == nil {
+		opt = defaultNormalizeOption
+	}
+	normalizeInst := newNormalizeInstance(emailAddress, opt)
+	if err = normalizeInst.runNormalize(); nil != err {
+		return
+	}
+	if err = normalizeInst.check(opt); nil != err {
+		return
+	}
+	normalizeInst.canonicalizeDomain(opt)
+	normalizedLocalPart, removedSubAddress := normalizeInst.normalizeLocalPart(opt)
+	if len(normalizedLocalPart) == 0 {
+		err = ErrEmptyLocalPartAfterNormalize
+		return
+	}
+	localPartNormalizer := &normalizeInst.localPartNormalizer
+	var comments []string
+	comments = append(comments, localPartNormalizer.comments...)
+	comments = append(comments, normalizeInst.domainComments.comments...)
+	var subAddressSeparator rune
+	var subAddressTag string
+	var subAddressOffset int
+	if normalizeInst.subdomainMailbox != "" {
+		subAddressTag = removedSubAddress
+	} else if ofst := normalizeInst.subAddressOffset; ofst >= 0 {
+		subAddressSeparator = localPartNormalizer.localPart[ofst]
+		subAddressTag = string(localPartNormalizer.localPart[ofst+1:])
+		subAddressOffset = localPartNormalizer.localPartPositions[ofst].runeOffset
+	}
+	parsedAddress = &ParsedAddress{
+		RawLocalPart:              string(normalizeInst.emailAddress[:normalizeInst.localPartEndOffset]),
+		CheckedLocalPart:          normalizeInst.resultCheckedLocalPart(opt),
+		NormalizedLocalPart:       normalizedLocalPart,
+		Domain:                    normalizeInst.resultDomainPart(),
+		NormalizedDomain:          normalizeInst.resultNormalizedDomainPart(),
+		DomainALabel:              normalizeInst.domainALabel,
+		DomainULabel:              normalizeInst.domainULabel,
+		RemovedSubAddress:         removedSubAddress,
+		SubAddressSeparator:       subAddressSeparator,
+		SubAddressTag:             subAddressTag,
+		SubAddressInDomain:        normalizeInst.subdomainMailbox != "",
+		SubAddressOffset:          subAddressOffset,
+		Comments:                  comments,
+		WasQuoted:                 localPartNormalizer.quotedInInput,
+		NeedQuote:                 localPartNormalizer.needQuote,
+		IsIPLiteral:               normalizeInst.checkedIsIPLiteralPositive,
+		WasBracketed:              normalizeInst.hasAddressLiteral,
+		AddressLiteralTag:         normalizeInst.addressLiteralTag,
+		IPAddressClass:            normalizeInst.ipAddressClass,
+		LocalPartHasI18NCharacter: localPartNormalizer.hasNonASCIICharacter,
+		DomainHasI18NCharacter:    normalizeInst.dnClass.IsIDNA(),
+		HasControlCharacter:       normalizeInst.invisibleCharacters.hasControlCharacter,
+		HasFormatCharacter:        normalizeInst.invisibleCharacters.hasFormatCharacter,
+		HasBidiControl:            normalizeInst.invisibleCharacters.hasBidiControl,
+		LocalPartIsMixedScript:    normalizeInst.localPartMixedScript,
+		DomainIsMixedScript:       normalizeInst.domainMixedScript,
+	}
+	return
+}
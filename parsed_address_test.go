@@ -0,0 +1,111 @@
+package emailaddressnormalize_test
+
+import (
+	"reflect"
+	"testing"
+
+	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
+)
+
+func doParseEmailAddressTest(t *testing.T, opt *emailaddressnormalize.NormalizeOption, inputAddr string, expectResult *emailaddressnormalize.ParsedAddress) {
+	result, err := emailaddressnormalize.ParseEmailAddress(inputAddr, opt)
+	if nil != err {
+		t.Errorf("unexpect error (addr: [%s], opt: %#v): %v", inputAddr, opt, err)
+		return
+	}
+	if !reflect.DeepEqual(result, expectResult) {
+		t.Errorf("unexpect result (addr: [%s], opt: %#v): %#v, expect %#v", inputAddr, opt, result, expectResult)
+	}
+}
+
+func TestParseEmailAddress_DefaultOpt(t *testing.T) {
+	doParseEmailAddressTest(t, nil, "U.se.r+subAddr@Example.Net", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "U.se.r+subAddr",
+		CheckedLocalPart:    "u.se.r+subaddr",
+		NormalizedLocalPart: "user",
+		Domain:              "example.net",
+		NormalizedDomain:    "example.net",
+		RemovedSubAddress:   "+subaddr",
+		SubAddressSeparator: '+',
+		SubAddressTag:       "subaddr",
+		SubAddressOffset:    6,
+	})
+	doParseEmailAddressTest(t, nil, "User(Comment \"A\")@Example.Net", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "User(Comment \"A\")",
+		CheckedLocalPart:    "user",
+		NormalizedLocalPart: "user",
+		Domain:              "example.net",
+		NormalizedDomain:    "example.net",
+		Comments:            []string{"Comment \"A\""},
+	})
+	doParseEmailAddressTest(t, nil, "User(A(\\(B\\)))@(C)Example.Net (D \"(E)\")", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "User(A(\\(B\\)))",
+		CheckedLocalPart:    "user",
+		NormalizedLocalPart: "user",
+		Domain:              "example.net",
+		NormalizedDomain:    "example.net",
+		Comments:            []string{"A((B))", "C", "D \"(E)\""},
+	})
+	if _, err := emailaddressnormalize.ParseEmailAddress("", nil); err != emailaddressnormalize.ErrGivenAddressTooShort {
+		t.Errorf("unexpect error content for empty address: %v", err)
+	}
+}
+
+func TestParseEmailAddress_Flags(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{
+		AllowQuotedLocalPart:             true,
+		AllowLocalPartInternationalChars: true,
+		AllowIPLiteral:                   true,
+	}
+	doParseEmailAddressTest(t, opt, "\"User One\"@[127.0.0.1]", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "\"User One\"",
+		CheckedLocalPart:    "\"user one\"",
+		NormalizedLocalPart: "\"user one\"",
+		Domain:              "[127.0.0.1]",
+		NormalizedDomain:    "[127.0.0.1]",
+		WasQuoted:           true,
+		NeedQuote:           true,
+		IsIPLiteral:         true,
+		WasBracketed:        true,
+		IPAddressClass:      emailaddressnormalize.IPAddressLoopback,
+	})
+	doParseEmailAddressTest(t, opt, "user@2001:DB8::1", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "user",
+		CheckedLocalPart:    "user",
+		NormalizedLocalPart: "user",
+		Domain:              "[IPv6:2001:db8::1]",
+		NormalizedDomain:    "[IPv6:2001:db8::1]",
+		IsIPLiteral:         true,
+		AddressLiteralTag:   emailaddressnormalize.AddressLiteralTagIPv6,
+		IPAddressClass:      emailaddressnormalize.IPAddressDocumentation,
+	})
+	doParseEmailAddressTest(t, opt, "user@[IPv6:2001:DB8::1]", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "user",
+		CheckedLocalPart:    "user",
+		NormalizedLocalPart: "user",
+		Domain:              "[IPv6:2001:db8::1]",
+		NormalizedDomain:    "[IPv6:2001:db8::1]",
+		IsIPLiteral:         true,
+		WasBracketed:        true,
+		AddressLiteralTag:   emailaddressnormalize.AddressLiteralTagIPv6,
+		IPAddressClass:      emailaddressnormalize.IPAddressDocumentation,
+	})
+	doParseEmailAddressTest(t, opt, "使用者@例子.台灣", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:              "使用者",
+		CheckedLocalPart:          "使用者",
+		NormalizedLocalPart:       "使用者",
+		Domain:                    "例子.台灣",
+		NormalizedDomain:          "例子.台灣",
+		LocalPartHasI18NCharacter: true,
+		DomainHasI18NCharacter:    true,
+	})
+	doParseEmailAddressTest(t, nil, "User\r\n@Ex\u200dample.Net", &emailaddressnormalize.ParsedAddress{
+		RawLocalPart:        "User\r\n",
+		CheckedLocalPart:    "user",
+		NormalizedLocalPart: "user",
+		Domain:              "example.net",
+		NormalizedDomain:    "example.net",
+		HasControlCharacter: true,
+		HasFormatCharacter:  true,
+	})
+}
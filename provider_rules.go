@@ -0,0 +1,238 @@
+package emailaddressnormalize
+
+import (
+	"strings"
+)
+
+// DeleteCharacter is the mapping target of characters to be removed from local part.
+const DeleteCharacter rune = -1
+
+// ProviderRule contain normalization rules of a mailbox provider.
+type ProviderRule struct {
+	// Domain is the canonical domain of the provider.
+	Domain string
+
+	// Aliases are the domains deliver to the same mailboxes as Domain.
+	Aliases []string
+
+	// SubAddressSeparators are the characters start sub-address in local part.
+	SubAddressSeparators []rune
+
+	// IgnoreDots indicate dots in local part does not matter.
+	IgnoreDots bool
+
+	// CharacterEquivalences map characters in local part into canonical one.
+	// Characters mapped to DeleteCharacter are removed.
+	CharacterEquivalences map[rune]rune
+
+	// SubdomainAddressing indicate the provider deliver `tag@mailbox.domain`
+	// to `mailbox@domain`.
+	SubdomainAddressing bool
+}
+
+// builtinProviderRules return a new copy of built-in provider rules.
+func builtinProviderRules() []*ProviderRule {
+	return []*ProviderRule{
+		{
+			Domain:               "gmail.com",
+			Aliases:              []string{"googlemail.com"},
+			SubAddressSeparators: []rune{'+'},
+			IgnoreDots:           true,
+		},
+		{
+			Domain:               "outlook.com",
+			SubAddressSeparators: []rune{'+'},
+		},
+		{
+			Domain:               "hotmail.com",
+			SubAddressSeparators: []rune{'+'},
+		},
+		{
+			Domain:               "live.com",
+			SubAddressSeparators: []rune{'+'},
+		},
+		{
+			Domain:               "msn.com",
+			SubAddressSeparators: []rune{'+'},
+		},
+		{
+			Domain:               "yahoo.com",
+			SubAddressSeparators: []rune{'-'},
+		},
+		{
+			Domain:               "ymail.com",
+			SubAddressSeparators: []rune{'-'},
+		},
+		{
+			Domain:               "icloud.com",
+			Aliases:              []string{"me.com", "mac.com"},
+			SubAddressSeparators: []rune{'+'},
+		},
+		{
+			Domain:               "fastmail.com",
+			SubAddressSeparators: []rune{'+'},
+			SubdomainAddressing:  true,
+		},
+		{
+			Domain:               "fastmail.fm",
+			SubAddressSeparators: []rune{'+'},
+			SubdomainAddressing:  true,
+		},
+		{
+			Domain:               "proton.me",
+			Aliases:              []string{"protonmail.com", "protonmail.ch", "pm.me"},
+			SubAddressSeparators: []rune{'+'},
+			IgnoreDots:           true,
+			CharacterEquivalences: map[rune]rune{
+				'-': DeleteCharacter,
+				'_': DeleteCharacter,
+			},
+		},
+		{
+			Domain:               "yandex.ru",
+			Aliases:              []string{"yandex.com", "ya.ru", "yandex.by", "yandex.kz", "yandex.ua"},
+			SubAddressSeparators: []rune{'+'},
+			CharacterEquivalences: map[rune]rune{
+				'.': '-',
+			},
+		},
+	}
+}
+
+// ProviderRules is a registry of ProviderRule indexed by domains and aliases.
+// The registry is not safe for modification concurrent with lookups.
+type ProviderRules struct {
+	rules       map[string]*ProviderRule
+	defaultRule *ProviderRule
+}
+
+// NewProviderRules create an instance of ProviderRules with given rules.
+func NewProviderRules(rules ...*ProviderRule) (r *ProviderRules) {
+	r = &ProviderRules{
+		rules: make(map[string]*ProviderRule),
+	}
+	for _, rule := range rules {
+		r.Set(rule)
+	}
+	return
+}
+
+// DefaultProviderRules create an instance of ProviderRules with built-in rules.
+// The returned instance can be modified without affecting other instances.
+func DefaultProviderRules() *ProviderRules {
+	return NewProviderRules(builtinProviderRules()...)
+}
+
+// normalizeRuleDomain return given domain in the form used as key of registry.
+func normalizeRuleDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(domain), ".")
+}
+
+// Set add given rule into registry. Existing rule of the same domain is
+// replaced together with its aliases. Existing rule having the domain or an
+// alias of given rule as alias only loses that alias.
+func (r *ProviderRules) Set(rule *ProviderRule) {
+	keys := make([]string, 0, len(rule.Aliases)+1)
+	keys = append(keys, normalizeRuleDomain(rule.Domain))
+	for _, alias := range rule.Aliases {
+		keys = append(keys, normalizeRuleDomain(alias))
+	}
+	for _, key := range keys {
+		if existed, ok := r.rules[key]; ok && (normalizeRuleDomain(existed.Domain) == key) {
+			r.Remove(key)
+		}
+	}
+	for _, key := range keys {
+		r.rules[key] = rule
+	}
+}
+
+// Remove remove the rule of given domain and its aliases from registry.
+func (r *ProviderRules) Remove(domain string) {
+	rule, ok := r.rules[normalizeRuleDomain(domain)]
+	if !ok {
+		return
+	}
+	for key, candidate := range r.rules {
+		if candidate == rule {
+			delete(r.rules, key)
+		}
+	}
+}
+
+// SetDefault set the rule for domains not found in registry.
+// Set to nil to leave such domains untouched.
+func (r *ProviderRules) SetDefault(rule *ProviderRule) {
+	r.defaultRule = rule
+}
+
+// Lookup find the rule of given domain part. The default rule is returned
+// when there is no rule for given domain part.
+func (r *ProviderRules) Lookup(domainPart string) *ProviderRule {
+	if rule, ok := r.rules[normalizeRuleDomain(domainPart)]; ok {
+		return rule
+	}
+	return r.defaultRule
+}
+
+// SubAddressingCharacters return sub-addressing characters of given domain part.
+// It is a SubAddressingCharactersFunc.
+func (r *ProviderRules) SubAddressingCharacters(domainPart string) (subAddressChars []rune) {
+	if rule := r.Lookup(domainPart); rule != nil {
+		return rule.SubAddressSeparators
+	}
+	return nil
+}
+
+// RemoveLocalPartDots check if dots in local part should be removed for given domain part.
+// It is a LocalPartDotsRemovalFunc.
+func (r *ProviderRules) RemoveLocalPartDots(domainPart string) (removeDots bool) {
+	if rule := r.Lookup(domainPart); rule != nil {
+		return rule.IgnoreDots
+	}
+	return false
+}
+
+// CanonicalDomain return the canonical domain of given domain part.
+// Given domain part is returned as-is when there is no rule for it.
+// It is a DomainCanonicalizeFunc.
+func (r *ProviderRules) CanonicalDomain(domainPart string) string {
+	if rule, ok := r.rules[normalizeRuleDomain(domainPart)]; ok {
+		return rule.Domain
+	}
+	return domainPart
+}
+
+// SubdomainAddressingBase return base domain if given domain part is a
+// subdomain address of a provider supports subdomain addressing.
+// It is a SubdomainAddressingFunc.
+func (r *ProviderRules) SubdomainAddressingBase(domainPart string) (baseDomain string) {
+	domainPart = normalizeRuleDomain(domainPart)
+	idx := strings.IndexByte(domainPart, '.')
+	if idx < 0 {
+		return ""
+	}
+	baseDomain = domainPart[idx+1:]
+	if rule, ok := r.rules[baseDomain]; ok && rule.SubdomainAddressing {
+		return baseDomain
+	}
+	return ""
+}
+
+// LocalPartCharacterMapping return character equivalences of local part for given domain part.
+// It is a LocalPartCharacterMappingFunc.
+func (r *ProviderRules) LocalPartCharacterMapping(domainPart string) (mapping map[rune]rune) {
+	if rule := r.Lookup(domainPart); rule != nil {
+		return rule.CharacterEquivalences
+	}
+	return nil
+}
+
+// ApplyTo set the per-domain callables of given option to use rules in this registry.
+func (r *ProviderRules) ApplyTo(opt *NormalizeOption) {
+	opt.RemoveSubAddressingWith = r.SubAddressingCharacters
+	opt.RemoveLocalPartDotsWith = r.RemoveLocalPartDots
+	opt.CanonicalizeDomainWith = r.CanonicalDomain
+	opt.SubdomainAddressingWith = r.SubdomainAddressingBase
+	opt.MapLocalPartCharactersWith = r.LocalPartCharacterMapping
+}
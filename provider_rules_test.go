@@ -0,0 +1,103 @@
+package emailaddressnormalize_test
+
+import (
+	"testing"
+
+	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
+)
+
+func TestProviderRules_Builtin(t *testing.T) {
+	rules := emailaddressnormalize.DefaultProviderRules()
+	opt := &emailaddressnormalize.NormalizeOption{}
+	rules.ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@GMail.com", "first.last+tag@gmail.com", "firstlast@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@GoogleMail.com", "first.last+tag@googlemail.com", "firstlast@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@Outlook.com", "first.last+tag@outlook.com", "first.last@outlook.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@Example.com", "first.last+tag@example.com", "first.last+tag@example.com", false)
+	if domain := rules.CanonicalDomain("mac.com"); domain != "icloud.com" {
+		t.Errorf("unexpect canonical domain of mac.com: %s", domain)
+	}
+	if domain := rules.CanonicalDomain("example.com"); domain != "example.com" {
+		t.Errorf("unexpect canonical domain of example.com: %s", domain)
+	}
+}
+
+func TestProviderRules_Override(t *testing.T) {
+	rules := emailaddressnormalize.DefaultProviderRules()
+	rules.Set(&emailaddressnormalize.ProviderRule{
+		Domain:               "gmail.com",
+		SubAddressSeparators: []rune{'+'},
+	})
+	rules.Set(&emailaddressnormalize.ProviderRule{
+		Domain:               "example.com",
+		Aliases:              []string{"example.net"},
+		SubAddressSeparators: []rune{'='},
+		IgnoreDots:           true,
+	})
+	rules.Remove("outlook.com")
+	opt := &emailaddressnormalize.NormalizeOption{}
+	rules.ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@GMail.com", "first.last+tag@gmail.com", "first.last@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@GoogleMail.com", "first.last+tag@googlemail.com", "first.last+tag@googlemail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last=Tag@Example.net", "first.last=tag@example.net", "firstlast@example.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@Outlook.com", "first.last+tag@outlook.com", "first.last+tag@outlook.com", false)
+	rules.SetDefault(&emailaddressnormalize.ProviderRule{
+		SubAddressSeparators: []rune{'+'},
+	})
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@Outlook.com", "first.last+tag@outlook.com", "first.last@outlook.com", false)
+	if builtinRule := emailaddressnormalize.DefaultProviderRules().Lookup("googlemail.com"); (builtinRule == nil) || (builtinRule.Domain != "gmail.com") {
+		t.Errorf("built-in rules modified: %#v", builtinRule)
+	}
+}
+
+func TestProviderRules_SubdomainAddressing(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{}
+	emailaddressnormalize.DefaultProviderRules().ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "Shop@User.FastMail.com", "shop@user.fastmail.com", "user@fastmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "User+Shop@FastMail.com", "user+shop@fastmail.com", "user@fastmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "Shop@a.User.FastMail.com", "shop@a.user.fastmail.com", "shop@a.user.fastmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "Shop@User.GMail.com", "shop@user.gmail.com", "shop@user.gmail.com", false)
+	parsedAddress, err := emailaddressnormalize.ParseEmailAddress("Shop@User.FastMail.com", opt)
+	if nil != err {
+		t.Fatalf("unexpect error: %v", err)
+	}
+	if (!parsedAddress.SubAddressInDomain) || (parsedAddress.SubAddressTag != "shop") || (parsedAddress.SubAddressSeparator != 0) {
+		t.Errorf("unexpect sub-address: %#v", parsedAddress)
+	}
+}
+
+func TestProviderRules_CharacterEquivalences(t *testing.T) {
+	opt := &emailaddressnormalize.NormalizeOption{}
+	emailaddressnormalize.DefaultProviderRules().ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "First.Last@Yandex.ru", "first.last@yandex.ru", "first-last@yandex.ru", false)
+	doNormalizeEmailAddressTest(t, opt, "First-Last+Tag@Ya.ru", "first-last+tag@ya.ru", "first-last@yandex.ru", false)
+	doNormalizeEmailAddressTest(t, opt, "First_Last.Name-X@ProtonMail.com", "first_last.name-x@protonmail.com", "firstlastnamex@proton.me", false)
+}
+
+func TestProviderRules_OverrideAlias(t *testing.T) {
+	rules := emailaddressnormalize.DefaultProviderRules()
+	rules.Set(&emailaddressnormalize.ProviderRule{
+		Domain:               "googlemail.com",
+		SubAddressSeparators: []rune{'='},
+	})
+	if rule := rules.Lookup("gmail.com"); (rule == nil) || (rule.Domain != "gmail.com") {
+		t.Errorf("unexpect rule of gmail.com: %#v", rule)
+	}
+	if rule := rules.Lookup("googlemail.com"); (rule == nil) || (rule.Domain != "googlemail.com") {
+		t.Errorf("unexpect rule of googlemail.com: %#v", rule)
+	}
+	opt := &emailaddressnormalize.NormalizeOption{}
+	rules.ApplyTo(opt)
+	doNormalizeEmailAddressTest(t, opt, "First.Last+Tag@GMail.com", "first.last+tag@gmail.com", "firstlast@gmail.com", false)
+	doNormalizeEmailAddressTest(t, opt, "First.Last=Tag@GoogleMail.com", "first.last=tag@googlemail.com", "first.last@googlemail.com", false)
+	rules.Set(&emailaddressnormalize.ProviderRule{
+		Domain:  "example.com",
+		Aliases: []string{"icloud.com"},
+	})
+	if rule := rules.Lookup("me.com"); rule != nil {
+		t.Errorf("expecting aliases of replaced rule removed: %#v", rule)
+	}
+	if domain := rules.CanonicalDomain("icloud.com"); domain != "example.com" {
+		t.Errorf("unexpect canonical domain of icloud.com: %s", domain)
+	}
+}
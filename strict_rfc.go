@@ -0,0 +1,243 @@
+package emailaddressnormalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Names of RFC 5321 grammar rules reported with GrammarError.
+const (
+	GrammarRuleMailbox        = "Mailbox"
+	GrammarRuleLocalPart      = "Local-part"
+	GrammarRuleDotString      = "Dot-string"
+	GrammarRuleAtom           = "Atom"
+	GrammarRuleQuotedString   = "Quoted-string"
+	GrammarRuleQcontentSMTP   = "QcontentSMTP"
+	GrammarRuleQuotedPairSMTP = "quoted-pairSMTP"
+	GrammarRuleDomain         = "Domain"
+	GrammarRuleSubDomain      = "sub-domain"
+	GrammarRuleLdhStr         = "Ldh-str"
+	GrammarRuleAddressLiteral = "address-literal"
+)
+
+var charactersOfAtextSymbol = ([]rune)("!#$%&'*+-/=?^_`{|}~")
+
+// isAtext check if given character `ch` is an atext of RFC 5322 (extended
+// with UTF8-non-ascii by RFC 6531).
+func isAtext(ch rune) bool {
+	return ((ch >= 'a') && (ch <= 'z')) ||
+		((ch >= 'A') && (ch <= 'Z')) ||
+		((ch >= '0') && (ch <= '9')) ||
+		(runesIndexRune(charactersOfAtextSymbol, ch) >= 0) ||
+		((ch > unicode.MaxASCII) && unicode.IsPrint(ch))
+}
+
+// isQtextSMTP check if given character `ch` is a qtextSMTP of RFC 5321
+// (extended with UTF8-non-ascii by RFC 6531).
+func isQtextSMTP(ch rune) bool {
+	return ((ch >= 32) && (ch <= 33)) ||
+		((ch >= 35) && (ch <= 91)) ||
+		((ch >= 93) && (ch <= 126)) ||
+		((ch > unicode.MaxASCII) && unicode.IsPrint(ch))
+}
+
+// isLetDig check if given character `ch` is a Let-dig of RFC 5321 (extended
+// with U-label characters by RFC 6531).
+func isLetDig(ch rune) bool {
+	return ((ch >= 'a') && (ch <= 'z')) ||
+		((ch >= 'A') && (ch <= 'Z')) ||
+		((ch >= '0') && (ch <= '9')) ||
+		((ch > unicode.MaxASCII) && (unicode.IsLetter(ch) || unicode.IsDigit(ch) || unicode.IsMark(ch)))
+}
+
+// strictRFCChecker validate given address with RFC 5321 Mailbox grammar.
+// Nothing is repaired: the first character does not match the grammar is
+// reported with the rule it violates.
+type strictRFCChecker struct {
+	allowIPv4Mapped bool
+
+	position      characterPosition
+	lastCharacter rune
+	literal       []rune
+
+	// endRule is the rule violated if given address ends at current state.
+	endRule string
+
+	failedRule string
+	failedAt   characterPosition
+}
+
+// fail record violation of given rule `rule` at current character.
+func (c *strictRFCChecker) fail(rule string) (nextState normalizeStateCallable) {
+	c.failedRule = rule
+	c.failedAt = c.position
+	return c.stateFailed
+}
+
+func (c *strictRFCChecker) stateFailed(ch rune) (nextState normalizeStateCallable) {
+	return nil
+}
+
+func (c *strictRFCChecker) stateEnd(ch rune) (nextState normalizeStateCallable) {
+	return c.fail(GrammarRuleMailbox)
+}
+
+func (c *strictRFCChecker) stateAddressLiteral(ch rune) (nextState normalizeStateCallable) {
+	if ch != ']' {
+		c.literal = append(c.literal, ch)
+		return nil
+	}
+	literal := string(c.literal)
+	_, tag, _, err := parseAddressLiteral(literal, c.allowIPv4Mapped)
+	if nil != err {
+		return c.fail(GrammarRuleAddressLiteral)
+	}
+	// IPv6-address-literal = "IPv6:" IPv6-addr, untagged form is not accepted.
+	if tagLen := len(AddressLiteralTagIPv6) + 1; (tag == AddressLiteralTagIPv6) && ((len(literal) < tagLen) || !strings.EqualFold(literal[:tagLen], AddressLiteralTagIPv6+":")) {
+		return c.fail(GrammarRuleAddressLiteral)
+	}
+	c.endRule = ""
+	return c.stateEnd
+}
+
+func (c *strictRFCChecker) stateSubDomainStart(ch rune) (nextState normalizeStateCallable) {
+	if !isLetDig(ch) {
+		return c.fail(GrammarRuleSubDomain)
+	}
+	c.endRule = ""
+	return c.stateSubDomain
+}
+
+func (c *strictRFCChecker) stateSubDomain(ch rune) (nextState normalizeStateCallable) {
+	switch {
+	case ch == '.':
+		if c.lastCharacter == '-' {
+			return c.fail(GrammarRuleLdhStr)
+		}
+		c.endRule = GrammarRuleDomain
+		return c.stateSubDomainStart
+	case ch == '-':
+		c.endRule = GrammarRuleLdhStr
+	case isLetDig(ch):
+		c.endRule = ""
+	default:
+		return c.fail(GrammarRuleSubDomain)
+	}
+	return nil
+}
+
+func (c *strictRFCChecker) stateDomainStart(ch rune) (nextState normalizeStateCallable) {
+	if ch == '[' {
+		c.endRule = GrammarRuleAddressLiteral
+		return c.stateAddressLiteral
+	}
+	return c.stateSubDomainStart(ch)
+}
+
+func (c *strictRFCChecker) stateQuotedStringEnd(ch rune) (nextState normalizeStateCallable) {
+	if ch != '@' {
+		return c.fail(GrammarRuleMailbox)
+	}
+	c.endRule = GrammarRuleDomain
+	return c.stateDomainStart
+}
+
+func (c *strictRFCChecker) stateQuotedPair(ch rune) (nextState normalizeStateCallable) {
+	if (ch < 32) || (ch > 126) {
+		return c.fail(GrammarRuleQuotedPairSMTP)
+	}
+	return c.stateQuotedString
+}
+
+func (c *strictRFCChecker) stateQuotedString(ch rune) (nextState normalizeStateCallable) {
+	switch {
+	case ch == '"':
+		c.endRule = GrammarRuleMailbox
+		return c.stateQuotedStringEnd
+	case ch == '\\':
+		return c.stateQuotedPair
+	case !isQtextSMTP(ch):
+		return c.fail(GrammarRuleQcontentSMTP)
+	}
+	return nil
+}
+
+func (c *strictRFCChecker) stateDotString(ch rune) (nextState normalizeStateCallable) {
+	switch {
+	case ch == '.':
+		if c.lastCharacter == '.' {
+			return c.fail(GrammarRuleDotString)
+		}
+	case ch == '@':
+		if c.lastCharacter == '.' {
+			return c.fail(GrammarRuleDotString)
+		}
+		c.endRule = GrammarRuleDomain
+		return c.stateDomainStart
+	case !isAtext(ch):
+		return c.fail(GrammarRuleAtom)
+	}
+	return nil
+}
+
+func (c *strictRFCChecker) stateLocalPartStart(ch rune) (nextState normalizeStateCallable) {
+	switch {
+	case ch == '"':
+		c.endRule = GrammarRuleQuotedString
+		return c.stateQuotedString
+	case ch == '.':
+		return c.fail(GrammarRuleDotString)
+	case ch == '@':
+		return c.fail(GrammarRuleLocalPart)
+	case !isAtext(ch):
+		return c.fail(GrammarRuleAtom)
+	}
+	return c.stateDotString
+}
+
+// check validate given address `emailAddress` and return the violated rule
+// and position of offending character. Empty rule is returned if given
+// address matches the grammar.
+func (c *strictRFCChecker) check(emailAddress string) (failedRule string, failedAt characterPosition) {
+	c.endRule = GrammarRuleMailbox
+	stateCallable := c.stateLocalPartStart
+	runeOffset := 0
+	for byteOffset, ch := range emailAddress {
+		c.position = characterPosition{
+			runeOffset: runeOffset,
+			byteOffset: byteOffset,
+			character:  ch,
+		}
+		runeOffset++
+		if nextStateCallable := stateCallable(ch); nil != nextStateCallable {
+			stateCallable = nextStateCallable
+		}
+		if c.failedRule != "" {
+			return c.failedRule, c.failedAt
+		}
+		c.lastCharacter = ch
+	}
+	if c.endRule != "" {
+		failedAt = characterPosition{
+			runeOffset: runeOffset,
+			byteOffset: len(emailAddress),
+		}
+		return c.endRule, failedAt
+	}
+	return "", failedAt
+}
+
+// checkStrictRFC validate given address with RFC 5321 Mailbox grammar.
+func (n *normalizeInstance) checkStrictRFC(opt *NormalizeOption) (err error) {
+	checker := strictRFCChecker{
+		allowIPv4Mapped: opt.AllowIPv4MappedAddressLiteral,
+	}
+	failedRule, failedAt := checker.check(n.emailAddressText)
+	if failedRule == "" {
+		return nil
+	}
+	return &GrammarError{
+		Rule: failedRule,
+		Err:  violationAt(opt, ErrStrictRFCViolation, failedAt),
+	}
+}
@@ -0,0 +1,95 @@
+package emailaddressnormalize
+
+import (
+	"strings"
+)
+
+// unquoteLocalPart remove quotes and escapes from given local part.
+func unquoteLocalPart(localPart string) []rune {
+	aux := ([]rune)(localPart)
+	if (len(aux) < 2) || (aux[0] != '"') || (aux[len(aux)-1] != '"') {
+		return aux
+	}
+	aux = aux[1 : len(aux)-1]
+	result := make([]rune, 0, len(aux))
+	inEscape := false
+	for _, ch := range aux {
+		if (ch == '\\') && !inEscape {
+			inEscape = true
+			continue
+		}
+		inEscape = false
+		result = append(result, ch)
+	}
+	return result
+}
+
+// checkSubAddressTag check given tag with the rules applied to local part.
+func checkSubAddressTag(tag string, opt *NormalizeOption) (err error) {
+	var invisibleCharacters invisibleCharacterRecord
+	n := normalizeLocalPartInstance{
+		localPart:    make([]rune, 0, len(tag)),
+		preserveCase: true,
+	}
+	for _, ch := range tag {
+		invisibleCharacters.inspect(characterPosition{character: ch})
+		n.commitToLocalPart(ch)
+	}
+	n.stopCheck()
+	switch {
+	case invisibleCharacters.hasControlCharacter:
+		return ErrGivenAddressContainControlCharacter
+	case invisibleCharacters.hasFormatCharacter:
+		return ErrGivenAddressContainFormatCharacter
+	case invisibleCharacters.hasBidiControl:
+		return ErrGivenAddressContainBidiControl
+	case (!opt.AllowQuotedLocalPart) && n.needQuote:
+		return ErrGivenAddressNeedQuote
+	case (!opt.AllowLocalPartSpecialChars) && n.hasUnsafeCharacter:
+		return ErrGivenAddressContainSpecialCharacter
+	case (!opt.AllowLocalPartInternationalChars) && n.hasNonASCIICharacter:
+		return ErrGivenAddressLocalPartContainI18NCharacter
+	}
+	return nil
+}
+
+// AttachSubAddressTag attach given tag to given normalized email address with
+// the first sub-addressing character of the domain part. The domain part is
+// canonicalized with CanonicalizeDomainWith option before looking up the
+// sub-addressing characters. The tag is checked with the rules applied to
+// local part and its case is kept.
+func AttachSubAddressTag(normalizedEmailAddress, tag string, opt *NormalizeOption) (emailAddress string, err error) {
+	if opt == nil {
+		opt = defaultNormalizeOption
+	}
+	idx := strings.LastIndexByte(normalizedEmailAddress, '@')
+	if idx < 1 {
+		err = ErrGivenAddressTooShort
+		return
+	}
+	if tag == "" {
+		return normalizedEmailAddress, nil
+	}
+	localPart := normalizedEmailAddress[:idx]
+	domainPart := normalizedEmailAddress[idx+1:]
+	lookupDomain := domainPart
+	if opt.CanonicalizeDomainWith != nil {
+		lookupDomain = opt.CanonicalizeDomainWith(lookupDomain)
+	}
+	var subAddrChars []rune
+	if opt.RemoveSubAddressingWith != nil {
+		subAddrChars = opt.RemoveSubAddressingWith(lookupDomain)
+	}
+	if len(subAddrChars) == 0 {
+		err = ErrNoSubAddressSeparator
+		return
+	}
+	if err = checkSubAddressTag(tag, opt); nil != err {
+		return
+	}
+	buf := unquoteLocalPart(localPart)
+	buf = append(buf, subAddrChars[0])
+	buf = append(buf, ([]rune)(tag)...)
+	emailAddress = requoteLocalPart(buf, true) + "@" + domainPart
+	return
+}
@@ -0,0 +1,61 @@
+package emailaddressnormalize_test
+
+import (
+	"testing"
+
+	emailaddressnormalize "github.com/yinyin/go-email-address-normalize"
+)
+
+func doAttachSubAddressTagTest(t *testing.T, opt *emailaddressnormalize.NormalizeOption, inputAddr, tag, expectAddr string) {
+	resultAddr, err := emailaddressnormalize.AttachSubAddressTag(inputAddr, tag, opt)
+	if nil != err {
+		t.Errorf("unexpect error (addr: [%s], tag: [%s]): %v", inputAddr, tag, err)
+		return
+	}
+	if resultAddr != expectAddr {
+		t.Errorf("unexpect result (addr: [%s], tag: [%s]): [%s], expect [%s]", inputAddr, tag, resultAddr, expectAddr)
+	}
+}
+
+func TestAttachSubAddressTag(t *testing.T) {
+	doAttachSubAddressTagTest(t, nil, "user@example.net", "billing", "user+billing@example.net")
+	doAttachSubAddressTagTest(t, nil, "user@example.net", "", "user@example.net")
+	opt := &emailaddressnormalize.NormalizeOption{}
+	emailaddressnormalize.DefaultProviderRules().ApplyTo(opt)
+	doAttachSubAddressTagTest(t, opt, "user@yahoo.com", "shop", "user-shop@yahoo.com")
+	doAttachSubAddressTagTest(t, opt, "user@googlemail.com", "shop", "user+shop@googlemail.com")
+	doAttachSubAddressTagTest(t, opt, "\"user one\"@gmail.com", "shop", "\"user one+shop\"@gmail.com")
+	doAttachSubAddressTagTest(t, opt, "user@gmail.com", "Shop.Tag", "user+Shop.Tag@gmail.com")
+	for _, c := range []struct {
+		tag string
+		err error
+	}{
+		{"a b", emailaddressnormalize.ErrGivenAddressNeedQuote},
+		{"a@b", emailaddressnormalize.ErrGivenAddressNeedQuote},
+		{"a..b", emailaddressnormalize.ErrGivenAddressNeedQuote},
+		{"ab.", emailaddressnormalize.ErrGivenAddressNeedQuote},
+		{"a#b", emailaddressnormalize.ErrGivenAddressContainSpecialCharacter},
+		{"\u8cfc\u7269", emailaddressnormalize.ErrGivenAddressLocalPartContainI18NCharacter},
+		{"a\r\nb", emailaddressnormalize.ErrGivenAddressContainControlCharacter},
+	} {
+		if _, err := emailaddressnormalize.AttachSubAddressTag("user@gmail.com", c.tag, opt); err != c.err {
+			t.Errorf("unexpect error content for tag %q: %v, expect %v", c.tag, err, c.err)
+		}
+	}
+	opt.AllowQuotedLocalPart = true
+	doAttachSubAddressTagTest(t, opt, "user@gmail.com", "a b", "\"user+a b\"@gmail.com")
+	if _, err := emailaddressnormalize.AttachSubAddressTag("user@example.net", "shop", opt); err != emailaddressnormalize.ErrNoSubAddressSeparator {
+		t.Errorf("unexpect error content for domain without sub-addressing: %v", err)
+	}
+}
+
+func TestParseEmailAddress_SubAddressRoundTrip(t *testing.T) {
+	parsedAddress, err := emailaddressnormalize.ParseEmailAddress("User+Billing@Example.Net", nil)
+	if nil != err {
+		t.Fatalf("unexpect error: %v", err)
+	}
+	if (parsedAddress.SubAddressSeparator != '+') || (parsedAddress.SubAddressTag != "billing") {
+		t.Errorf("unexpect sub-address: %q, %q", parsedAddress.SubAddressSeparator, parsedAddress.SubAddressTag)
+	}
+	doAttachSubAddressTagTest(t, nil, parsedAddress.NormalizedEmailAddress(), parsedAddress.SubAddressTag, parsedAddress.CheckedEmailAddress())
+}
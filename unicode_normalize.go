@@ -0,0 +1,16 @@
+package emailaddressnormalize
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// composeText return NFC form of given text.
+func composeText(text string) string {
+	return norm.NFC.String(text)
+}
+
+// foldText return NFKC form of given text with full case folding applied.
+func foldText(text string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFD.String(text)))
+}